@@ -0,0 +1,77 @@
+package libgenders
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/ryanmoran/libgenders/internal"
+)
+
+func (d Database) Canonical(name string) (string, bool) {
+	if index, ok := d.lookup(name); ok {
+		return d.nodes[index].Name, true
+	}
+
+	return "", false
+}
+
+func (d Database) Aliases(name string) []string {
+	index, ok := d.lookup(name)
+	if !ok {
+		return nil
+	}
+
+	return slices.Clone(d.altnames[index])
+}
+
+func (d Database) lookup(name string) (int, bool) {
+	if index, ok := d.names[name]; ok {
+		return index, true
+	}
+
+	index, ok := d.aliases[name]
+	return index, ok
+}
+
+func (d *Database) indexNames() error {
+	d.aliases = make(map[string]int)
+	d.altnames = make([][]string, len(d.nodes))
+	if d.altname != "" {
+		for index, node := range d.nodes {
+			for alias := range strings.SplitSeq(node.Attributes[d.altname], ",") {
+				if alias == "" || alias == node.Name {
+					continue
+				}
+
+				if other, ok := d.names[alias]; ok {
+					return fmt.Errorf("failed to index aliases: alias %s of node %s collides with node %s", alias, node.Name, d.nodes[other].Name)
+				}
+
+				if other, ok := d.aliases[alias]; ok {
+					if other != index {
+						return fmt.Errorf("failed to index aliases: alias %s of node %s collides with alias of node %s", alias, node.Name, d.nodes[other].Name)
+					}
+					continue
+				}
+
+				d.aliases[alias] = index
+				d.altnames[index] = append(d.altnames[index], alias)
+			}
+			slices.Sort(d.altnames[index])
+		}
+	}
+
+	d.terms = make(map[string]internal.Set, len(d.attrs)+len(d.names)+len(d.aliases))
+	maps.Copy(d.terms, d.attrs)
+	for _, names := range []map[string]int{d.names, d.aliases} {
+		for name, index := range names {
+			if _, ok := d.terms[name]; !ok {
+				d.terms[name] = internal.Set{index}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,188 @@
+package libgenders_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ryanmoran/libgenders"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testAlias(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		path     string
+		database libgenders.Database
+	)
+
+	it.Before(func() {
+		path = filepath.Join(t.TempDir(), "genders")
+		Expect(os.WriteFile(path, []byte(`node[1-3] compute
+node1 altname="node1.example.com,node1-bmc"
+node2 altname=node2-bmc,gpu
+login1 login,bmc=login1-bmc
+`), 0600)).To(Succeed())
+
+		var err error
+		database, err = libgenders.NewDatabase(path)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	context("Canonical", func() {
+		it("resolves aliases and canonical names to the canonical name", func() {
+			name, ok := database.Canonical("node1-bmc")
+			Expect(ok).To(BeTrue())
+			Expect(name).To(Equal("node1"))
+
+			name, ok = database.Canonical("node3")
+			Expect(ok).To(BeTrue())
+			Expect(name).To(Equal("node3"))
+
+			_, ok = database.Canonical("no-such-node")
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	context("Aliases", func() {
+		it("returns the sorted aliases of a node", func() {
+			Expect(database.Aliases("node1")).To(Equal([]string{"node1-bmc", "node1.example.com"}))
+			Expect(database.Aliases("node2-bmc")).To(Equal([]string{"node2-bmc"}))
+			Expect(database.Aliases("node3")).To(BeEmpty())
+			Expect(database.Aliases("no-such-node")).To(BeEmpty())
+		})
+
+		it("lists an alias once when a node repeats it", func() {
+			Expect(os.WriteFile(path, []byte(`node1 altname="b,a,b"`+"\n"), 0600)).To(Succeed())
+
+			database, err := libgenders.NewDatabase(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(database.Aliases("node1")).To(Equal([]string{"a", "b"}))
+		})
+	})
+
+	context("GetNode and GetNodeAttr", func() {
+		it("resolve aliases to the canonical node", func() {
+			node, ok := database.GetNode("node1.example.com")
+			Expect(ok).To(BeTrue())
+			Expect(node.Name).To(Equal("node1"))
+
+			value, ok := database.GetNodeAttr("node2-bmc", "altname")
+			Expect(ok).To(BeTrue())
+			Expect(value).To(Equal("node2-bmc"))
+		})
+	})
+
+	context("Query", func() {
+		it("matches node names and aliases as terms", func() {
+			nodes, err := database.Query("node1-bmc || node3 || login1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names(nodes)).To(Equal([]string{"node1", "node3", "login1"}))
+
+			nodes, err = database.Query("compute -- node2-bmc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names(nodes)).To(Equal([]string{"node1", "node3"}))
+		})
+
+		it("prefers attributes over node names", func() {
+			nodes, err := database.Query("gpu")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names(nodes)).To(Equal([]string{"node2"}))
+		})
+	})
+
+	context("TestQuery", func() {
+		it("resolves aliases and matches node name terms", func() {
+			ok, err := database.TestQuery("node1-bmc", "compute && ~gpu")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+
+			ok, err = database.TestQuery("node1.example.com", "node1 || node2")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+
+			ok, err = database.TestQuery("node3", "node1-bmc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	context("when the alias attribute is configured", func() {
+		it("indexes that attribute instead", func() {
+			database, err := libgenders.NewDatabase(path, libgenders.WithAltnameAttribute("bmc"))
+			Expect(err).NotTo(HaveOccurred())
+
+			name, ok := database.Canonical("login1-bmc")
+			Expect(ok).To(BeTrue())
+			Expect(name).To(Equal("login1"))
+
+			_, ok = database.Canonical("node1-bmc")
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	context("when the database is read from the cache", func() {
+		it("indexes the aliases", func() {
+			cache := filepath.Join(t.TempDir(), "genders.cache")
+			_, err := libgenders.NewDatabase(path, libgenders.WithCache(cache))
+			Expect(err).NotTo(HaveOccurred())
+
+			database, stats, err := libgenders.NewDatabaseContext(t.Context(), path, libgenders.WithCache(cache), libgenders.WithAltnameAttribute("bmc"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stats.Cached).To(BeTrue())
+
+			name, ok := database.Canonical("login1-bmc")
+			Expect(ok).To(BeTrue())
+			Expect(name).To(Equal("login1"))
+		})
+
+		it("does not index the default attribute when another is configured", func() {
+			Expect(os.WriteFile(path, []byte("node1 altname=node2,bmc=node1-bmc\nnode2 compute\n"), 0600)).To(Succeed())
+
+			cache := filepath.Join(t.TempDir(), "genders.cache")
+			_, err := libgenders.NewDatabase(path, libgenders.WithCache(cache), libgenders.WithAltnameAttribute("bmc"))
+			Expect(err).NotTo(HaveOccurred())
+
+			database, stats, err := libgenders.NewDatabaseContext(t.Context(), path, libgenders.WithCache(cache), libgenders.WithAltnameAttribute("bmc"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stats.Cached).To(BeTrue())
+			Expect(database.Aliases("node1")).To(Equal([]string{"node1-bmc"}))
+		})
+	})
+
+	context("failure cases", func() {
+		context("when an alias is the name of another node", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(path, []byte("node1 altname=node2\nnode2 compute\n"), 0600)).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				_, err := libgenders.NewDatabase(path)
+				Expect(err).To(MatchError("failed to index aliases: alias node2 of node node1 collides with node node2"))
+			})
+		})
+
+		context("when two nodes share an alias", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(path, []byte("node1 altname=bmc\nnode2 altname=bmc\n"), 0600)).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				_, err := libgenders.NewDatabase(path)
+				Expect(err).To(MatchError("failed to index aliases: alias bmc of node node2 collides with alias of node node1"))
+			})
+		})
+	})
+}
+
+func names(nodes []libgenders.Node) []string {
+	var names []string
+	for _, node := range nodes {
+		names = append(names, node.Name)
+	}
+
+	return names
+}
@@ -0,0 +1,365 @@
+package libgenders
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/ryanmoran/libgenders/internal"
+)
+
+const (
+	cacheMagic   = "GENDERS\x00"
+	cacheVersion = 4
+)
+
+var (
+	ErrStaleCache = errors.New("stale database cache")
+
+	errCorruptCache = errors.New("truncated or corrupt data")
+)
+
+func (d Database) MarshalBinary() ([]byte, error) {
+	var (
+		body    encoder
+		strings []string
+		interns = make(map[string]uint64)
+	)
+
+	intern := func(s string) uint64 {
+		if index, ok := interns[s]; ok {
+			return index
+		}
+
+		index := uint64(len(strings))
+		strings = append(strings, s)
+		interns[s] = index
+
+		return index
+	}
+
+	body.uvarint(uint64(len(d.nodes)))
+	for _, node := range d.nodes {
+		body.uvarint(intern(node.Name))
+		body.uvarint(uint64(len(node.Attributes)))
+		for _, key := range slices.Sorted(maps.Keys(node.Attributes)) {
+			body.uvarint(intern(key))
+			body.uvarint(intern(node.Attributes[key]))
+		}
+	}
+
+	for _, index := range []map[string]internal.Set{d.attrs, d.attrvals} {
+		body.uvarint(uint64(len(index)))
+		for _, key := range slices.Sorted(maps.Keys(index)) {
+			body.uvarint(intern(key))
+			body.uvarint(uint64(len(index[key])))
+			for _, i := range index[key] {
+				body.uvarint(uint64(i))
+			}
+		}
+	}
+
+	body.uvarint(uint64(len(d.conflicts)))
+	for _, conflict := range d.conflicts {
+		body.uvarint(intern(conflict.Node))
+		body.uvarint(intern(conflict.Attribute))
+		for _, definition := range []Definition{conflict.Previous, conflict.Current} {
+			body.uvarint(intern(definition.Value))
+			body.uvarint(intern(definition.Path))
+			body.uvarint(uint64(definition.Line))
+		}
+	}
+
+	var header encoder
+	header.bytes([]byte(cacheMagic))
+	header.uvarint(cacheVersion)
+	header.string(d.root)
+	header.uvarint(uint64(d.policy))
+	header.string(d.altname)
+	header.uvarint(uint64(d.maxLine))
+
+	header.uvarint(uint64(len(d.sources)))
+	for _, source := range d.sources {
+		header.string(source.path)
+		header.bytes(source.checksum[:])
+	}
+
+	header.uvarint(uint64(len(d.listings)))
+	for _, listing := range d.listings {
+		header.string(listing.pattern)
+		header.bool(listing.dir)
+		header.uvarint(uint64(len(listing.matches)))
+		for _, match := range listing.matches {
+			header.string(match)
+		}
+	}
+
+	header.uvarint(uint64(len(strings)))
+	for _, s := range strings {
+		header.string(s)
+	}
+
+	return append(header.Bytes(), body.Bytes()...), nil
+}
+
+func (d *Database) UnmarshalBinary(data []byte) error {
+	decoder := decoder{data: data}
+	if magic := decoder.bytes(len(cacheMagic)); string(magic) != cacheMagic {
+		return errors.New("failed to decode database: invalid header")
+	}
+
+	if version := decoder.uvarint(); version != cacheVersion {
+		return fmt.Errorf("failed to decode database: unsupported version %d", version)
+	}
+
+	database := Database{
+		root:    decoder.string(),
+		policy:  MergePolicy(decoder.uvarint()),
+		altname: decoder.string(),
+		maxLine: int(decoder.uvarint()),
+	}
+
+	database.sources = make([]source, decoder.length())
+	for i := range database.sources {
+		database.sources[i].path = decoder.string()
+		copy(database.sources[i].checksum[:], decoder.bytes(sha256.Size))
+	}
+	database.checksum = checksum(database.sources)
+
+	database.listings = make([]listing, decoder.length())
+	for i := range database.listings {
+		database.listings[i].pattern = decoder.string()
+		database.listings[i].dir = decoder.bool()
+		database.listings[i].matches = make([]string, decoder.length())
+		for j := range database.listings[i].matches {
+			database.listings[i].matches[j] = decoder.string()
+		}
+	}
+
+	strings := make([]string, decoder.length())
+	for i := range strings {
+		strings[i] = decoder.string()
+	}
+
+	lookup := func() string {
+		index := decoder.uvarint()
+		if index >= uint64(len(strings)) {
+			decoder.fail()
+			return ""
+		}
+
+		return strings[index]
+	}
+
+	database.nodes = make([]Node, decoder.length())
+	database.names = make(map[string]int, len(database.nodes))
+	database.indices = make(internal.Set, len(database.nodes))
+	for i := range database.nodes {
+		node := Node{Name: lookup()}
+		if count := decoder.length(); count > 0 {
+			node.Attributes = make(map[string]string, count)
+			for range count {
+				key := lookup()
+				node.Attributes[key] = lookup()
+			}
+		}
+
+		database.nodes[i] = node
+		database.names[node.Name] = i
+		database.indices[i] = i
+	}
+
+	for _, index := range []*map[string]internal.Set{&database.attrs, &database.attrvals} {
+		*index = make(map[string]internal.Set)
+		for range decoder.length() {
+			key := lookup()
+			set := make(internal.Set, decoder.length())
+			for j := range set {
+				set[j] = int(decoder.uvarint())
+				if set[j] < 0 || set[j] >= len(database.nodes) {
+					decoder.fail()
+				}
+			}
+			(*index)[key] = set
+		}
+	}
+
+	if count := decoder.length(); count > 0 {
+		database.conflicts = make([]Conflict, count)
+		for i := range database.conflicts {
+			conflict := Conflict{Node: lookup(), Attribute: lookup()}
+			for _, definition := range []*Definition{&conflict.Previous, &conflict.Current} {
+				definition.Value = lookup()
+				definition.Path = lookup()
+				definition.Line = int(decoder.uvarint())
+			}
+			database.conflicts[i] = conflict
+		}
+	}
+
+	if len(decoder.data) > 0 {
+		decoder.fail()
+	}
+
+	if decoder.err != nil {
+		return fmt.Errorf("failed to decode database: %w", decoder.err)
+	}
+
+	if err := database.indexNames(); err != nil {
+		return err
+	}
+
+	*d = database
+	return nil
+}
+
+func readCache(path, root string, config config) (Database, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Database{}, err
+	}
+
+	var database Database
+	if err := database.UnmarshalBinary(data); err != nil {
+		return Database{}, err
+	}
+
+	if err := database.validate(root, config); err != nil {
+		return Database{}, err
+	}
+
+	return database, nil
+}
+
+func writeCache(path string, database Database) error {
+	data, err := database.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
+
+func (d Database) validate(root string, config config) error {
+	if d.root != filepath.Clean(root) || d.policy != config.mergePolicy || d.maxLine != config.maxLineLength {
+		return ErrStaleCache
+	}
+
+	for _, listing := range d.listings {
+		matches, err := listing.expand()
+		if err != nil || !slices.Equal(matches, listing.matches) {
+			return ErrStaleCache
+		}
+	}
+
+	for _, source := range d.sources {
+		content, err := os.ReadFile(source.path)
+		if err != nil || sha256.Sum256(content) != source.checksum {
+			return ErrStaleCache
+		}
+	}
+
+	return nil
+}
+
+type encoder struct {
+	bytes.Buffer
+}
+
+func (e *encoder) uvarint(value uint64) {
+	e.Write(binary.AppendUvarint(nil, value))
+}
+
+func (e *encoder) bool(value bool) {
+	if value {
+		e.uvarint(1)
+	} else {
+		e.uvarint(0)
+	}
+}
+
+func (e *encoder) bytes(value []byte) {
+	e.Write(value)
+}
+
+func (e *encoder) string(value string) {
+	e.uvarint(uint64(len(value)))
+	e.WriteString(value)
+}
+
+type decoder struct {
+	data []byte
+	err  error
+}
+
+func (d *decoder) fail() {
+	if d.err == nil {
+		d.err = errCorruptCache
+	}
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+
+	value, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.fail()
+		return 0
+	}
+	d.data = d.data[n:]
+
+	return value
+}
+
+func (d *decoder) length() int {
+	length := d.uvarint()
+	if length > uint64(len(d.data)) {
+		d.fail()
+		return 0
+	}
+
+	return int(length)
+}
+
+func (d *decoder) bool() bool {
+	return d.uvarint() != 0
+}
+
+func (d *decoder) bytes(n int) []byte {
+	if d.err != nil || n > len(d.data) {
+		d.fail()
+		return make([]byte, n)
+	}
+
+	value := d.data[:n]
+	d.data = d.data[n:]
+
+	return value
+}
+
+func (d *decoder) string() string {
+	return string(d.bytes(d.length()))
+}
@@ -0,0 +1,228 @@
+package libgenders_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ryanmoran/libgenders"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testCache(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	context("MarshalBinary/UnmarshalBinary", func() {
+		var database libgenders.Database
+
+		it.Before(func() {
+			var err error
+			database, err = libgenders.NewDatabase("./testdata/genders.query_1_hostrange")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it("round trips the database", func() {
+			data, err := database.MarshalBinary()
+			Expect(err).NotTo(HaveOccurred())
+
+			var decoded libgenders.Database
+			Expect(decoded.UnmarshalBinary(data)).To(Succeed())
+			Expect(decoded.GetNodes()).To(Equal(database.GetNodes()))
+
+			for _, query := range []string{"attr1", "attr4=val4", "~attr5", "attr7 -- attr8=val8"} {
+				expected, err := database.Query(query)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(decoded.Query(query)).To(Equal(expected))
+			}
+
+			Expect(decoded.MarshalBinary()).To(Equal(data))
+		})
+
+		it("keeps the aliases of the database", func() {
+			path := filepath.Join(t.TempDir(), "genders")
+			Expect(os.WriteFile(path, []byte("node1 altname=node1-bmc\nnode2 bmc=node2-bmc\n"), 0600)).To(Succeed())
+
+			for _, options := range [][]libgenders.Option{nil, {libgenders.WithAltnameAttribute("bmc")}} {
+				database, err := libgenders.NewDatabase(path, options...)
+				Expect(err).NotTo(HaveOccurred())
+
+				data, err := database.MarshalBinary()
+				Expect(err).NotTo(HaveOccurred())
+
+				var decoded libgenders.Database
+				Expect(decoded.UnmarshalBinary(data)).To(Succeed())
+
+				for _, alias := range []string{"node1-bmc", "node2-bmc"} {
+					expected, ok := database.Canonical(alias)
+					name, found := decoded.Canonical(alias)
+					Expect(found).To(Equal(ok))
+					Expect(name).To(Equal(expected))
+
+					count, err := decoded.Count(alias)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(database.Count(alias)).To(Equal(count))
+				}
+			}
+		})
+
+		context("failure cases", func() {
+			context("when the header is invalid", func() {
+				it("returns an error", func() {
+					var decoded libgenders.Database
+					err := decoded.UnmarshalBinary([]byte("node1 attr1\n"))
+					Expect(err).To(MatchError("failed to decode database: invalid header"))
+				})
+			})
+
+			context("when the version is not supported", func() {
+				it("returns an error", func() {
+					var decoded libgenders.Database
+					err := decoded.UnmarshalBinary([]byte("GENDERS\x00\x63"))
+					Expect(err).To(MatchError("failed to decode database: unsupported version 99"))
+				})
+			})
+
+			context("when the data is truncated", func() {
+				it("returns an error", func() {
+					data, err := database.MarshalBinary()
+					Expect(err).NotTo(HaveOccurred())
+
+					var decoded libgenders.Database
+					err = decoded.UnmarshalBinary(data[:len(data)-1])
+					Expect(err).To(MatchError("failed to decode database: truncated or corrupt data"))
+				})
+			})
+		})
+	})
+
+	context("WithCache", func() {
+		var dir, path, cache string
+
+		it.Before(func() {
+			dir = t.TempDir()
+			path = filepath.Join(dir, "genders")
+			cache = filepath.Join(dir, "genders.cache")
+
+			Expect(os.Mkdir(filepath.Join(dir, "genders.d"), 0700)).To(Succeed())
+			Expect(os.WriteFile(path, []byte("node[1-2] attr1\n#include genders.d/*\n"), 0600)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "genders.d", "a"), []byte("node1 attr2=val2\n"), 0600)).To(Succeed())
+		})
+
+		loadAndStat := func(options ...libgenders.Option) (libgenders.Database, time.Time) {
+			database, err := libgenders.NewDatabase(path, append(options, libgenders.WithCache(cache))...)
+			Expect(err).NotTo(HaveOccurred())
+
+			info, err := os.Stat(cache)
+			Expect(err).NotTo(HaveOccurred())
+
+			return database, info.ModTime()
+		}
+
+		it("writes the cache and loads from it while the sources are unchanged", func() {
+			database, written := loadAndStat()
+			Expect(database.GetNodes()).To(HaveLen(2))
+
+			old := written.Add(-time.Hour)
+			Expect(os.Chtimes(cache, old, old)).To(Succeed())
+
+			cached, modTime := loadAndStat()
+			Expect(modTime).To(Equal(old))
+			Expect(cached.GetNodes()).To(Equal(database.GetNodes()))
+		})
+
+		context("when a source file changes", func() {
+			it("rejects the stale cache", func() {
+				_, written := loadAndStat()
+				old := written.Add(-time.Hour)
+				Expect(os.Chtimes(cache, old, old)).To(Succeed())
+
+				Expect(os.WriteFile(filepath.Join(dir, "genders.d", "a"), []byte("node3 attr2=val2\n"), 0600)).To(Succeed())
+
+				database, modTime := loadAndStat()
+				Expect(modTime).NotTo(Equal(old))
+				Expect(database.GetNodes()).To(HaveLen(3))
+			})
+		})
+
+		context("when an include pattern matches a new file", func() {
+			it("rejects the stale cache", func() {
+				loadAndStat()
+				Expect(os.WriteFile(filepath.Join(dir, "genders.d", "b"), []byte("node4\n"), 0600)).To(Succeed())
+
+				database, _ := loadAndStat()
+				Expect(database.GetNodes()).To(HaveLen(3))
+			})
+		})
+
+		context("when the merge policy differs", func() {
+			it("rejects the stale cache", func() {
+				_, written := loadAndStat()
+				old := written.Add(-time.Hour)
+				Expect(os.Chtimes(cache, old, old)).To(Succeed())
+
+				_, modTime := loadAndStat(libgenders.WithMergePolicy(libgenders.MergeFirstWins))
+				Expect(modTime).NotTo(Equal(old))
+			})
+		})
+
+		context("when the maximum line length differs", func() {
+			it("rejects the stale cache", func() {
+				loadAndStat()
+
+				_, err := libgenders.NewDatabase(path, libgenders.WithMaxLineLength(10), libgenders.WithCache(cache))
+				Expect(err).To(MatchError(ContainSubstring("line exceeds maximum length of 10 bytes")))
+			})
+		})
+
+		context("when the database has merge conflicts", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(dir, "genders.d", "a"), []byte("node1 attr1=override\n"), 0600)).To(Succeed())
+			})
+
+			it("caches the database with its conflicts", func() {
+				database, written := loadAndStat()
+				Expect(database.Conflicts()).To(HaveLen(1))
+
+				old := written.Add(-time.Hour)
+				Expect(os.Chtimes(cache, old, old)).To(Succeed())
+
+				cached, stats, err := libgenders.NewDatabaseContext(t.Context(), path, libgenders.WithCache(cache))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(stats.Cached).To(BeTrue())
+				Expect(cached.Conflicts()).To(Equal(database.Conflicts()))
+			})
+
+			it("reports the conflicts to the warning handler when loading from the cache", func() {
+				var conflicts []libgenders.Conflict
+				options := []libgenders.Option{
+					libgenders.WithMergePolicy(libgenders.MergeWarnOnConflict),
+					libgenders.WithConflictHandler(func(conflict libgenders.Conflict) {
+						conflicts = append(conflicts, conflict)
+					}),
+					libgenders.WithCache(cache),
+				}
+
+				_, _, err := libgenders.NewDatabaseContext(t.Context(), path, options...)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, stats, err := libgenders.NewDatabaseContext(t.Context(), path, options...)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(stats.Cached).To(BeTrue())
+				Expect(conflicts).To(HaveLen(2))
+				Expect(conflicts[1]).To(Equal(conflicts[0]))
+			})
+		})
+
+		context("when the cache is corrupt", func() {
+			it("loads the database from the source", func() {
+				Expect(os.WriteFile(cache, []byte("GENDERS\x00\x01garbage"), 0600)).To(Succeed())
+
+				database, _ := loadAndStat()
+				Expect(database.GetNodes()).To(HaveLen(2))
+			})
+		})
+	})
+}
@@ -0,0 +1,14 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestGendersInventory(t *testing.T) {
+	suite := spec.New(" genders-inventory", spec.Report(report.Terminal{}))
+	suite("Inventory", testInventory)
+	suite.Run(t)
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ryanmoran/libgenders"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "genders-inventory: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout io.Writer) error {
+	path := os.Getenv("GENDERS_FILE")
+	if path == "" {
+		path = libgenders.DefaultGendersFilepath
+	}
+
+	flags := flag.NewFlagSet("genders-inventory", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	list := flags.Bool("list", false, "print the inventory of all groups and hosts")
+	host := flags.String("host", "", "print the variables of a single host")
+	flags.StringVar(&path, "file", path, "path to the genders file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *list == (*host != "") {
+		return errors.New("expected exactly one of --list or --host")
+	}
+
+	database, err := libgenders.NewDatabase(path)
+	if err != nil {
+		return err
+	}
+
+	var output any
+	if *list {
+		output = inventory(database)
+	} else {
+		node, ok := database.GetNode(*host)
+		if !ok {
+			return fmt.Errorf("unknown host %q", *host)
+		}
+		output = hostvars(node)
+	}
+
+	encoder := json.NewEncoder(stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(output)
+}
+
+type group struct {
+	Hosts    []string `json:"hosts,omitempty"`
+	Children []string `json:"children,omitempty"`
+}
+
+func inventory(database libgenders.Database) map[string]any {
+	var (
+		groups   = make(map[string]*group)
+		vars     = make(map[string]map[string]any)
+		children []string
+	)
+
+	add := func(name, host string) {
+		g, ok := groups[name]
+		if !ok {
+			g = &group{}
+			groups[name] = g
+			children = append(children, name)
+		}
+
+		if !slices.Contains(g.Hosts, host) {
+			g.Hosts = append(g.Hosts, host)
+		}
+	}
+
+	for _, attr := range database.GetAttrs() {
+		if attr == "" {
+			continue
+		}
+
+		for _, node := range database.GetNodes() {
+			value, ok := node.Attributes[attr]
+			switch {
+			case !ok:
+				continue
+
+			case value == "":
+				add(groupName(attr), node.Name)
+
+			default:
+				add(groupName(attr+"_"+value), node.Name)
+			}
+		}
+	}
+
+	for _, node := range database.GetNodes() {
+		vars[node.Name] = hostvars(node)
+		if len(vars[node.Name]) == 0 {
+			add("ungrouped", node.Name)
+		}
+	}
+
+	slices.Sort(children)
+	output := map[string]any{
+		"_meta": map[string]any{"hostvars": vars},
+		"all":   group{Children: children},
+	}
+
+	for name, g := range groups {
+		output[name] = g
+	}
+
+	return output
+}
+
+func hostvars(node libgenders.Node) map[string]any {
+	vars := make(map[string]any, len(node.Attributes))
+	for key, value := range node.Attributes {
+		if key == "" {
+			continue
+		}
+
+		if value == "" {
+			vars[identifier(key)] = true
+			continue
+		}
+
+		vars[identifier(key)] = value
+	}
+
+	return vars
+}
+
+func groupName(name string) string {
+	name = identifier(name)
+	if slices.Contains([]string{"_meta", "all", "ungrouped"}, name) {
+		return "genders_" + name
+	}
+
+	return name
+}
+
+func identifier(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+
+		return '_'
+	}, name)
+
+	if name != "" && name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+
+	return name
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testInventory(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		path string
+	)
+
+	it.Before(func() {
+		path = filepath.Join(t.TempDir(), "genders")
+		Expect(os.WriteFile(path, []byte("node[1-2] compute,os=rhel9\nnode2 gpu,rack=a-1\nlogin1\n"), 0600)).To(Succeed())
+	})
+
+	context("--list", func() {
+		it("prints groups for attributes and hostvars for each node", func() {
+			var stdout bytes.Buffer
+			Expect(run([]string{"--file", path, "--list"}, &stdout)).To(Succeed())
+			Expect(stdout.String()).To(MatchJSON(`{
+				"_meta": {
+					"hostvars": {
+						"node1": {"compute": true, "os": "rhel9"},
+						"node2": {"compute": true, "os": "rhel9", "gpu": true, "rack": "a-1"},
+						"login1": {}
+					}
+				},
+				"all": {"children": ["compute", "gpu", "os_rhel9", "rack_a_1", "ungrouped"]},
+				"compute": {"hosts": ["node1", "node2"]},
+				"gpu": {"hosts": ["node2"]},
+				"os_rhel9": {"hosts": ["node1", "node2"]},
+				"rack_a_1": {"hosts": ["node2"]},
+				"ungrouped": {"hosts": ["login1"]}
+			}`))
+		})
+
+		context("when the genders file is given by the environment", func() {
+			it.Before(func() {
+				t.Setenv("GENDERS_FILE", path)
+			})
+
+			it("loads that file", func() {
+				var stdout bytes.Buffer
+				Expect(run([]string{"--list"}, &stdout)).To(Succeed())
+				Expect(stdout.String()).To(ContainSubstring(`"login1"`))
+			})
+		})
+
+		context("when an attribute has an empty name", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(path, []byte("node1 a,\nnode2 ,\n"), 0600)).To(Succeed())
+			})
+
+			it("skips the attribute", func() {
+				var stdout bytes.Buffer
+				Expect(run([]string{"--file", path, "--list"}, &stdout)).To(Succeed())
+				Expect(stdout.String()).To(MatchJSON(`{
+					"_meta": {"hostvars": {"node1": {"a": true}, "node2": {}}},
+					"all": {"children": ["a", "ungrouped"]},
+					"a": {"hosts": ["node1"]},
+					"ungrouped": {"hosts": ["node2"]}
+				}`))
+			})
+		})
+
+		context("when an attribute names a reserved group", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(path, []byte("node1 all,compute\nnode2 _meta,ungrouped\nlogin1\n"), 0600)).To(Succeed())
+			})
+
+			it("prefixes the group name", func() {
+				var stdout bytes.Buffer
+				Expect(run([]string{"--file", path, "--list"}, &stdout)).To(Succeed())
+				Expect(stdout.String()).To(MatchJSON(`{
+					"_meta": {
+						"hostvars": {
+							"node1": {"all": true, "compute": true},
+							"node2": {"_meta": true, "ungrouped": true},
+							"login1": {}
+						}
+					},
+					"all": {"children": ["compute", "genders__meta", "genders_all", "genders_ungrouped", "ungrouped"]},
+					"compute": {"hosts": ["node1"]},
+					"genders__meta": {"hosts": ["node2"]},
+					"genders_all": {"hosts": ["node1"]},
+					"genders_ungrouped": {"hosts": ["node2"]},
+					"ungrouped": {"hosts": ["login1"]}
+				}`))
+			})
+		})
+	})
+
+	context("--host", func() {
+		it("prints the variables of the host", func() {
+			var stdout bytes.Buffer
+			Expect(run([]string{"--file", path, "--host", "node2"}, &stdout)).To(Succeed())
+			Expect(stdout.String()).To(MatchJSON(`{"compute": true, "os": "rhel9", "gpu": true, "rack": "a-1"}`))
+		})
+	})
+
+	context("failure cases", func() {
+		context("when neither --list nor --host is given", func() {
+			it("returns an error", func() {
+				err := run([]string{"--file", path}, &bytes.Buffer{})
+				Expect(err).To(MatchError("expected exactly one of --list or --host"))
+			})
+		})
+
+		context("when the host does not exist", func() {
+			it("returns an error", func() {
+				err := run([]string{"--file", path, "--host", "node9"}, &bytes.Buffer{})
+				Expect(err).To(MatchError(`unknown host "node9"`))
+			})
+		})
+
+		context("when the genders file does not exist", func() {
+			it("returns an error", func() {
+				err := run([]string{"--file", "no-such-file", "--list"}, &bytes.Buffer{})
+				Expect(err).To(MatchError(ContainSubstring("no-such-file: no such file or directory")))
+			})
+		})
+	})
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ryanmoran/libgenders"
+)
+
+func runClush(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("clush", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	path := flags.String("file", libgenders.DefaultGendersFilepath, "path to the genders file")
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("clush: %w", err)
+	}
+
+	if flags.NArg() == 0 {
+		return fmt.Errorf("clush: expected one of map, all, list, or reverse")
+	}
+
+	command, args := flags.Arg(0), flags.Args()[1:]
+	switch command {
+	case "map", "reverse":
+		if len(args) != 1 {
+			return fmt.Errorf("clush: %s expected 1 argument, got %d", command, len(args))
+		}
+
+	case "all", "list":
+		if len(args) != 0 {
+			return fmt.Errorf("clush: %s expected no arguments, got %d", command, len(args))
+		}
+
+	default:
+		return fmt.Errorf("clush: unknown command %q", command)
+	}
+
+	database, err := libgenders.NewDatabase(*path)
+	if err != nil {
+		return err
+	}
+
+	var lines []string
+	switch command {
+	case "map":
+		nodes, err := database.Query(args[0])
+		if err != nil {
+			return err
+		}
+
+		if len(nodes) > 0 {
+			lines = append(lines, libgenders.CompressHostlist(nodeNames(nodes)))
+		}
+
+	case "all":
+		if nodes := database.GetNodes(); len(nodes) > 0 {
+			lines = append(lines, libgenders.CompressHostlist(nodeNames(nodes)))
+		}
+
+	case "list":
+		lines = database.GetAttrs()
+
+	case "reverse":
+		node, ok := database.GetNode(args[0])
+		if !ok {
+			return fmt.Errorf("clush: unknown node %q", args[0])
+		}
+
+		for _, attr := range database.GetAttrs() {
+			if _, ok := node.Attributes[attr]; ok {
+				lines = append(lines, attr)
+			}
+		}
+	}
+
+	if len(lines) == 0 {
+		return nil
+	}
+
+	_, err = fmt.Fprintln(stdout, strings.Join(lines, "\n"))
+	return err
+}
+
+func nodeNames(nodes []libgenders.Node) []string {
+	names := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		names = append(names, node.Name)
+	}
+
+	return names
+}
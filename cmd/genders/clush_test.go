@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testClush(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		path string
+	)
+
+	it.Before(func() {
+		path = filepath.Join(t.TempDir(), "genders")
+		Expect(os.WriteFile(path, []byte("node[1-8] compute\nnode[5-6],node8 gpu,os=rhel9\nlogin1 login\n"), 0600)).To(Succeed())
+	})
+
+	context("map", func() {
+		it("prints the compressed hostlist of the group", func() {
+			var stdout bytes.Buffer
+			Expect(run([]string{"clush", "-file", path, "map", "gpu"}, &stdout)).To(Succeed())
+			Expect(stdout.String()).To(Equal("node[5-6,8]\n"))
+		})
+
+		it("accepts queries as group names", func() {
+			var stdout bytes.Buffer
+			Expect(run([]string{"clush", "-file", path, "map", "compute&&~gpu"}, &stdout)).To(Succeed())
+			Expect(stdout.String()).To(Equal("node[1-4,7]\n"))
+		})
+
+		context("when the group is empty", func() {
+			it("prints nothing", func() {
+				var stdout bytes.Buffer
+				Expect(run([]string{"clush", "-file", path, "map", "no-such-group"}, &stdout)).To(Succeed())
+				Expect(stdout.String()).To(BeEmpty())
+			})
+		})
+	})
+
+	context("all", func() {
+		it("prints the compressed hostlist of every node", func() {
+			var stdout bytes.Buffer
+			Expect(run([]string{"clush", "-file", path, "all"}, &stdout)).To(Succeed())
+			Expect(stdout.String()).To(Equal("node[1-8],login1\n"))
+		})
+	})
+
+	context("list", func() {
+		it("prints every group", func() {
+			var stdout bytes.Buffer
+			Expect(run([]string{"clush", "-file", path, "list"}, &stdout)).To(Succeed())
+			Expect(stdout.String()).To(Equal("compute\ngpu\nlogin\nos\n"))
+		})
+	})
+
+	context("reverse", func() {
+		it("prints the groups of the node", func() {
+			var stdout bytes.Buffer
+			Expect(run([]string{"clush", "-file", path, "reverse", "node5"}, &stdout)).To(Succeed())
+			Expect(stdout.String()).To(Equal("compute\ngpu\nos\n"))
+		})
+	})
+
+	context("failure cases", func() {
+		context("when no command is given", func() {
+			it("returns an error", func() {
+				err := run([]string{"clush", "-file", path}, &bytes.Buffer{})
+				Expect(err).To(MatchError("clush: expected one of map, all, list, or reverse"))
+			})
+		})
+
+		context("when the command is unknown", func() {
+			it("returns an error", func() {
+				err := run([]string{"clush", "-file", path, "bogus"}, &bytes.Buffer{})
+				Expect(err).To(MatchError(`clush: unknown command "bogus"`))
+			})
+		})
+
+		context("when map is missing its group", func() {
+			it("returns an error", func() {
+				err := run([]string{"clush", "-file", path, "map"}, &bytes.Buffer{})
+				Expect(err).To(MatchError("clush: map expected 1 argument, got 0"))
+			})
+		})
+
+		context("when the reversed node does not exist", func() {
+			it("returns an error", func() {
+				err := run([]string{"clush", "-file", path, "reverse", "node9"}, &bytes.Buffer{})
+				Expect(err).To(MatchError(`clush: unknown node "node9"`))
+			})
+		})
+	})
+}
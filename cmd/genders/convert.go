@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ryanmoran/libgenders"
+	"go.yaml.in/yaml/v3"
+)
+
+func runConvert(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("convert", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	from := flags.String("from", "genders", "format of the input file: genders, json, or yaml")
+	to := flags.String("to", "json", "format of the output: genders, json, or yaml")
+	index := flags.Bool("index", false, "include the attribute index in json or yaml output")
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("convert: %w", err)
+	}
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("convert: expected 1 input file, got %d", flags.NArg())
+	}
+
+	database, err := readDatabase(flags.Arg(0), *from)
+	if err != nil {
+		return err
+	}
+
+	switch *to {
+	case "genders":
+		_, err = database.WriteTo(stdout)
+		return err
+
+	case "json":
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(database.Document(*index))
+
+	case "yaml":
+		encoder := yaml.NewEncoder(stdout)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(database.Document(*index)); err != nil {
+			return err
+		}
+		return encoder.Close()
+
+	default:
+		return fmt.Errorf("convert: unknown output format %q", *to)
+	}
+}
+
+func readDatabase(path, format string) (libgenders.Database, error) {
+	if format == "genders" {
+		return libgenders.NewDatabase(path)
+	}
+
+	var unmarshal func([]byte, any) error
+	switch format {
+	case "json":
+		unmarshal = json.Unmarshal
+
+	case "yaml":
+		unmarshal = yaml.Unmarshal
+
+	default:
+		return libgenders.Database{}, fmt.Errorf("convert: unknown input format %q", format)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return libgenders.Database{}, err
+	}
+
+	var database libgenders.Database
+	if err := unmarshal(content, &database); err != nil {
+		return libgenders.Database{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	return database, nil
+}
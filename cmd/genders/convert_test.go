@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testConvert(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		dir, path string
+	)
+
+	it.Before(func() {
+		dir = t.TempDir()
+		path = filepath.Join(dir, "genders")
+		Expect(os.WriteFile(path, []byte("node[1-3] attr1,attr2=val2\nnode4\n"), 0600)).To(Succeed())
+	})
+
+	it("converts a genders file to json", func() {
+		var stdout bytes.Buffer
+		Expect(run([]string{"convert", "--from", "genders", "--to", "json", path}, &stdout)).To(Succeed())
+		Expect(stdout.String()).To(MatchJSON(`{
+			"nodes": [
+				{"name": "node1", "attributes": {"attr1": "", "attr2": "val2"}},
+				{"name": "node2", "attributes": {"attr1": "", "attr2": "val2"}},
+				{"name": "node3", "attributes": {"attr1": "", "attr2": "val2"}},
+				{"name": "node4"}
+			]
+		}`))
+	})
+
+	it("includes the attribute index when requested", func() {
+		var stdout bytes.Buffer
+		Expect(run([]string{"convert", "--index", path}, &stdout)).To(Succeed())
+		Expect(stdout.String()).To(ContainSubstring(`"attr2=val2": [`))
+	})
+
+	it("converts a genders file to yaml and back, preserving hostranges", func() {
+		var stdout bytes.Buffer
+		Expect(run([]string{"convert", "--to", "yaml", path}, &stdout)).To(Succeed())
+		Expect(stdout.String()).To(HavePrefix("nodes:\n  - name: node1\n"))
+
+		yamlPath := filepath.Join(dir, "genders.yaml")
+		Expect(os.WriteFile(yamlPath, stdout.Bytes(), 0600)).To(Succeed())
+
+		stdout.Reset()
+		Expect(run([]string{"convert", "--from", "yaml", "--to", "genders", yamlPath}, &stdout)).To(Succeed())
+		Expect(stdout.String()).To(Equal("node[1-3] attr1,attr2=val2\nnode4\n"))
+	})
+
+	it("converts json back to genders", func() {
+		jsonPath := filepath.Join(dir, "genders.json")
+		Expect(os.WriteFile(jsonPath, []byte(`{"nodes": [{"name": "node1", "attributes": {"attr1": "50%"}}, {"name": "node2", "attributes": {"attr1": "50%"}}]}`), 0600)).To(Succeed())
+
+		var stdout bytes.Buffer
+		Expect(run([]string{"convert", "--from", "json", "--to", "genders", jsonPath}, &stdout)).To(Succeed())
+		Expect(stdout.String()).To(Equal("node[1-2] attr1=50%%\n"))
+	})
+
+	context("failure cases", func() {
+		context("when the input format is unknown", func() {
+			it("returns an error", func() {
+				err := run([]string{"convert", "--from", "xml", path}, &bytes.Buffer{})
+				Expect(err).To(MatchError(`convert: unknown input format "xml"`))
+			})
+		})
+
+		context("when the output format is unknown", func() {
+			it("returns an error", func() {
+				err := run([]string{"convert", "--to", "xml", path}, &bytes.Buffer{})
+				Expect(err).To(MatchError(`convert: unknown output format "xml"`))
+			})
+		})
+
+		context("when the input cannot be parsed", func() {
+			it("returns an error", func() {
+				err := run([]string{"convert", "--from", "json", path}, &bytes.Buffer{})
+				Expect(err).To(MatchError(ContainSubstring("failed to parse " + path)))
+			})
+		})
+	})
+}
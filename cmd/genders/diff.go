@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/ryanmoran/libgenders"
+)
+
+func runDiff(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	asJSON := flags.Bool("json", false, "print the changes as JSON")
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("diff: %w", err)
+	}
+
+	if flags.NArg() != 2 {
+		return fmt.Errorf("diff: expected 2 genders files, got %d", flags.NArg())
+	}
+
+	a, err := libgenders.NewDatabase(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	b, err := libgenders.NewDatabase(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	changes := libgenders.Diff(a, b)
+	if *asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(changes)
+	}
+
+	var output strings.Builder
+	for _, name := range changes.Added {
+		fmt.Fprintf(&output, "+ %s\n", name)
+	}
+
+	for _, name := range changes.Removed {
+		fmt.Fprintf(&output, "- %s\n", name)
+	}
+
+	for _, node := range changes.Nodes {
+		fmt.Fprintf(&output, "~ %s\n", node.Name)
+		for _, key := range slices.Sorted(maps.Keys(node.Added)) {
+			fmt.Fprintf(&output, "    + %s\n", formatAttr(key, node.Added[key]))
+		}
+
+		for _, key := range slices.Sorted(maps.Keys(node.Removed)) {
+			fmt.Fprintf(&output, "    - %s\n", formatAttr(key, node.Removed[key]))
+		}
+
+		for _, key := range slices.Sorted(maps.Keys(node.Changed)) {
+			change := node.Changed[key]
+			fmt.Fprintf(&output, "    ~ %s -> %s\n", formatAttr(key, change.Old), formatAttr(key, change.New))
+		}
+	}
+
+	for _, query := range changes.Queries {
+		fmt.Fprintf(&output, "? %s\n", query.Query)
+		if len(query.Added) > 0 {
+			fmt.Fprintf(&output, "    + %s\n", strings.Join(query.Added, ","))
+		}
+
+		if len(query.Removed) > 0 {
+			fmt.Fprintf(&output, "    - %s\n", strings.Join(query.Removed, ","))
+		}
+	}
+
+	_, err = io.WriteString(stdout, output.String())
+	return err
+}
+
+func formatAttr(key, value string) string {
+	if value == "" {
+		return key
+	}
+
+	return fmt.Sprintf("%s=%s", key, value)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ryanmoran/libgenders"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDiff(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		a, b string
+	)
+
+	it.Before(func() {
+		dir := t.TempDir()
+
+		a = filepath.Join(dir, "genders.a")
+		Expect(os.WriteFile(a, []byte("node[1-2] attr1,attr2=val2\nnode2 attr3\n"), 0600)).To(Succeed())
+
+		b = filepath.Join(dir, "genders.b")
+		Expect(os.WriteFile(b, []byte("node[2-3] attr1,attr2=val2\nnode2 attr2=other,attr4\n"), 0600)).To(Succeed())
+	})
+
+	it("prints the changes between two genders files", func() {
+		var stdout bytes.Buffer
+		Expect(run([]string{"diff", a, b}, &stdout)).To(Succeed())
+		Expect(stdout.String()).To(Equal(`+ node3
+- node1
+~ node2
+    + attr4
+    - attr3
+    ~ attr2=val2 -> attr2=other
+? attr1
+    + node3
+    - node1
+? attr2
+    + node3
+    - node1
+? attr2=other
+    + node2
+? attr2=val2
+    + node3
+    - node1,node2
+? attr3
+    - node2
+? attr4
+    + node2
+`))
+	})
+
+	context("when the -json flag is given", func() {
+		it("prints the changes as JSON", func() {
+			var stdout bytes.Buffer
+			Expect(run([]string{"diff", "-json", a, b}, &stdout)).To(Succeed())
+
+			var changes libgenders.Changes
+			Expect(json.Unmarshal(stdout.Bytes(), &changes)).To(Succeed())
+			Expect(changes.Added).To(Equal([]string{"node3"}))
+			Expect(changes.Removed).To(Equal([]string{"node1"}))
+			Expect(changes.Nodes).To(HaveLen(1))
+			Expect(changes.Queries).To(HaveLen(6))
+		})
+	})
+
+	context("failure cases", func() {
+		context("when the wrong number of files is given", func() {
+			it("returns an error", func() {
+				err := run([]string{"diff", a}, &bytes.Buffer{})
+				Expect(err).To(MatchError("diff: expected 2 genders files, got 1"))
+			})
+		})
+
+		context("when a file cannot be loaded", func() {
+			it("returns an error", func() {
+				err := run([]string{"diff", a, "no-such-file"}, &bytes.Buffer{})
+				Expect(err).To(MatchError(ContainSubstring("no-such-file: no such file or directory")))
+			})
+		})
+	})
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ryanmoran/libgenders"
+	"github.com/ryanmoran/libgenders/hosts"
+)
+
+func runHosts(args []string, stdout io.Writer) error {
+	config := hosts.Config{Attributes: make(map[string]string)}
+
+	flags := flag.NewFlagSet("hosts", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	path := flags.String("file", libgenders.DefaultGendersFilepath, "path to the genders file")
+	format := flags.String("format", "hosts", "output format: hosts, zone, or reverse")
+	flags.StringVar(&config.Query, "query", "", "genders query selecting the nodes to write")
+	flags.StringVar(&config.Domain, "domain", "", "domain of the generated names")
+	flags.Func("attr", "attribute holding an address, optionally attr=suffix for <node><suffix> names (repeatable)", func(value string) error {
+		attr, suffix, _ := strings.Cut(value, "=")
+		if attr == "" {
+			return fmt.Errorf("expected attr or attr=suffix, got %q", value)
+		}
+
+		config.Attributes[attr] = suffix
+		return nil
+	})
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("hosts: %w", err)
+	}
+
+	if len(config.Attributes) == 0 {
+		config.Attributes["ip"] = ""
+	}
+
+	var write func(io.Writer, []hosts.Record, string) error
+	switch *format {
+	case "hosts":
+		write = hosts.WriteHosts
+
+	case "zone":
+		write = hosts.WriteZone
+
+	case "reverse":
+		write = hosts.WriteReverseZone
+
+	default:
+		return fmt.Errorf("hosts: unknown format %q", *format)
+	}
+
+	database, err := libgenders.NewDatabase(*path)
+	if err != nil {
+		return err
+	}
+
+	records, err := hosts.Records(database, config)
+	if err != nil {
+		return err
+	}
+
+	return write(stdout, records, config.Domain)
+}
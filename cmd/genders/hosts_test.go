@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testHosts(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		path string
+	)
+
+	it.Before(func() {
+		path = filepath.Join(t.TempDir(), "genders")
+		Expect(os.WriteFile(path, []byte("node1 compute,ip=10.1.2.3,bmc=10.9.2.3\nlogin1 ip=10.1.0.1\n"), 0600)).To(Succeed())
+	})
+
+	it("writes /etc/hosts entries for the ip attribute by default", func() {
+		var stdout bytes.Buffer
+		Expect(run([]string{"hosts", "-file", path}, &stdout)).To(Succeed())
+		Expect(stdout.String()).To(Equal("10.1.2.3\tnode1\n10.1.0.1\tlogin1\n"))
+	})
+
+	it("writes zone records for the mapped attributes", func() {
+		var stdout bytes.Buffer
+		Expect(run([]string{"hosts", "-file", path, "-format", "zone", "-query", "compute", "-attr", "ip", "-attr", "bmc=-bmc", "-domain", "example.com"}, &stdout)).To(Succeed())
+		Expect(stdout.String()).To(Equal("$ORIGIN example.com.\nnode1\tIN\tA\t10.1.2.3\nnode1-bmc\tIN\tA\t10.9.2.3\n"))
+	})
+
+	context("failure cases", func() {
+		context("when the format is unknown", func() {
+			it("returns an error", func() {
+				err := run([]string{"hosts", "-file", path, "-format", "csv"}, &bytes.Buffer{})
+				Expect(err).To(MatchError(`hosts: unknown format "csv"`))
+			})
+		})
+
+		context("when an address is invalid", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(path, []byte("node1 ip=banana\n"), 0600)).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				err := run([]string{"hosts", "-file", path}, &bytes.Buffer{})
+				Expect(err).To(MatchError(`node node1 attribute ip: invalid address "banana"`))
+			})
+		})
+	})
+}
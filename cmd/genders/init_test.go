@@ -0,0 +1,19 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestGenders(t *testing.T) {
+	suite := spec.New(" genders", spec.Report(report.Terminal{}))
+	suite("Clush", testClush)
+	suite("Convert", testConvert)
+	suite("Diff", testDiff)
+	suite("Hosts", testHosts)
+	suite("Render", testRender)
+	suite("Slurm", testSlurm)
+	suite.Run(t)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+const usage = `usage: genders <command> [arguments]
+
+commands:
+  clush    act as a clustershell group source (map, all, list, reverse)
+  convert  convert a database between genders, json, and yaml
+  diff     print the changes between two genders files
+  hosts    write /etc/hosts entries or DNS zone records
+  render   execute a text/template with the genders database
+  slurm    write slurm.conf NodeName and PartitionName lines
+`
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "genders: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing command\n\n%s", usage)
+	}
+
+	switch args[0] {
+	case "clush":
+		return runClush(args[1:], stdout)
+
+	case "convert":
+		return runConvert(args[1:], stdout)
+
+	case "diff":
+		return runDiff(args[1:], stdout)
+
+	case "hosts":
+		return runHosts(args[1:], stdout)
+
+	case "render":
+		return runRender(args[1:], stdout)
+
+	case "slurm":
+		return runSlurm(args[1:], stdout)
+
+	case "help", "-h", "--help":
+		_, err := fmt.Fprint(stdout, usage)
+		return err
+
+	default:
+		return fmt.Errorf("unknown command %q\n\n%s", args[0], usage)
+	}
+}
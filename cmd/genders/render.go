@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ryanmoran/libgenders"
+	"github.com/ryanmoran/libgenders/render"
+)
+
+func runRender(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("render", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	path := flags.String("file", libgenders.DefaultGendersFilepath, "path to the genders file")
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("render: %w", err)
+	}
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("render: expected 1 argument, got %d", flags.NArg())
+	}
+
+	text, err := os.ReadFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	database, err := libgenders.NewDatabase(*path)
+	if err != nil {
+		return err
+	}
+
+	return render.Execute(stdout, filepath.Base(flags.Arg(0)), string(text), database)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testRender(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		path, template string
+	)
+
+	it.Before(func() {
+		dir := t.TempDir()
+		path = filepath.Join(dir, "genders")
+		Expect(os.WriteFile(path, []byte("node[1-2] gpu\nnode1 ip=10.0.0.1\nnode2 ip=10.0.0.2\n"), 0600)).To(Succeed())
+
+		template = filepath.Join(dir, "hosts.tmpl")
+		Expect(os.WriteFile(template, []byte(`{{range query "gpu"}}{{.Name}} {{attr .Name "ip"}}
+{{end}}`), 0600)).To(Succeed())
+	})
+
+	it("executes the template against the database", func() {
+		var stdout bytes.Buffer
+		Expect(run([]string{"render", "-file", path, template}, &stdout)).To(Succeed())
+		Expect(stdout.String()).To(Equal("node1 10.0.0.1\nnode2 10.0.0.2\n"))
+	})
+
+	context("failure cases", func() {
+		context("when no template is given", func() {
+			it("returns an error", func() {
+				err := run([]string{"render", "-file", path}, &bytes.Buffer{})
+				Expect(err).To(MatchError("render: expected 1 argument, got 0"))
+			})
+		})
+
+		context("when the template does not exist", func() {
+			it("returns an error", func() {
+				err := run([]string{"render", "-file", path, "no-such-template"}, &bytes.Buffer{})
+				Expect(err).To(MatchError(os.ErrNotExist))
+			})
+		})
+	})
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ryanmoran/libgenders"
+	"github.com/ryanmoran/libgenders/slurm"
+)
+
+func runSlurm(args []string, stdout io.Writer) error {
+	config := slurm.Config{Fields: make(map[string]string)}
+
+	flags := flag.NewFlagSet("slurm", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	path := flags.String("file", libgenders.DefaultGendersFilepath, "path to the genders file")
+	flags.StringVar(&config.Query, "query", "", "genders query selecting the nodes to write")
+	flags.StringVar(&config.Partition, "partition", "", "attribute naming the partition of each node")
+	flags.Func("field", "attr=Field mapping a genders attribute to a NodeName field (repeatable)", func(value string) error {
+		attr, field, ok := strings.Cut(value, "=")
+		if !ok || attr == "" || field == "" {
+			return fmt.Errorf("expected attr=Field, got %q", value)
+		}
+
+		config.Fields[attr] = field
+		return nil
+	})
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("slurm: %w", err)
+	}
+
+	if flags.NArg() != 0 {
+		return fmt.Errorf("slurm: unexpected arguments %q", flags.Args())
+	}
+
+	database, err := libgenders.NewDatabase(*path)
+	if err != nil {
+		return err
+	}
+
+	return slurm.Generate(stdout, database, config)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testSlurm(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		path string
+	)
+
+	it.Before(func() {
+		path = filepath.Join(t.TempDir(), "genders")
+		Expect(os.WriteFile(path, []byte("node[1-4] compute,cpus=64,partition=batch\nnode4 drain\nlogin1 login\n"), 0600)).To(Succeed())
+	})
+
+	it("writes the slurm node configuration", func() {
+		var stdout bytes.Buffer
+		Expect(run([]string{"slurm", "-file", path, "-query", "compute && ~drain", "-field", "cpus=CPUs", "-partition", "partition"}, &stdout)).To(Succeed())
+		Expect(stdout.String()).To(Equal("NodeName=node[1-3] CPUs=64\nPartitionName=batch Nodes=node[1-3]\n"))
+	})
+
+	context("failure cases", func() {
+		context("when a field mapping is malformed", func() {
+			it("returns an error", func() {
+				err := run([]string{"slurm", "-file", path, "-field", "cpus"}, &bytes.Buffer{})
+				Expect(err).To(MatchError(ContainSubstring(`expected attr=Field, got "cpus"`)))
+			})
+		})
+	})
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ryanmoran/libgenders"
+	"github.com/ryanmoran/libgenders/httpapi"
+)
+
+func main() {
+	path := flag.String("file", libgenders.DefaultGendersFilepath, "path to the genders file")
+	addr := flag.String("addr", ":8080", "address to listen on")
+	interval := flag.Duration("interval", 10*time.Second, "how often to check the genders file for changes")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, *path, *addr, *interval); err != nil {
+		log.Fatalf("gendersd: %s", err)
+	}
+}
+
+func run(ctx context.Context, path, addr string, interval time.Duration) error {
+	watcher, err := libgenders.NewWatcher(path, interval)
+	if err != nil {
+		return err
+	}
+
+	watcher.Subscribe(func(event libgenders.WatchEvent) {
+		if event.Err != nil {
+			log.Printf("gendersd: keeping previous database: %s", event.Err)
+			return
+		}
+
+		log.Printf("gendersd: reloaded %s (%d added, %d removed, %d changed)", path, len(event.Changes.Added), len(event.Changes.Removed), len(event.Changes.Nodes))
+	})
+
+	go func() { _ = watcher.Run(ctx) }()
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           httpapi.NewHandler(watcher.Handle()),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdown, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		_ = server.Shutdown(shutdown)
+	}()
+
+	log.Printf("gendersd: serving %s on %s", path, addr)
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
@@ -1,15 +1,23 @@
 package libgenders
 
 import (
-	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
-	"os"
+	"iter"
+	"maps"
+	"slices"
+	"time"
 
 	"github.com/ryanmoran/libgenders/internal"
 )
 
 const DefaultGendersFilepath = "/etc/genders"
 
+var ErrNodeNotFound = errors.New("node not found")
+
 type Database struct {
 	nodes []Node
 	names map[string]int
@@ -17,69 +25,145 @@ type Database struct {
 	attrs    map[string]internal.Set
 	attrvals map[string]internal.Set
 	indices  internal.Set
+
+	root     string
+	policy   MergePolicy
+	maxLine  int
+	sources  []source
+	listings []listing
+	checksum [sha256.Size]byte
+
+	conflicts []Conflict
+
+	hostname func() (string, error)
+	altname  string
+	aliases  map[string]int
+	altnames [][]string
+	terms    map[string]internal.Set
 }
 
-func NewDatabase(path string) (Database, error) {
-	file, err := os.Open(path)
-	if err != nil {
-		return Database{}, err
-	}
-	defer file.Close()
+type Stats struct {
+	Lines      int
+	Nodes      int
+	Attributes int
+	Merges     int
+	Cached     bool
+	Duration   time.Duration
+}
 
-	database := Database{
-		nodes:    []Node{},
-		names:    make(map[string]int),
-		attrs:    make(map[string]internal.Set),
-		attrvals: make(map[string]internal.Set),
+func NewDatabase(path string, options ...Option) (Database, error) {
+	database, _, err := NewDatabaseContext(context.Background(), path, options...)
+	return database, err
+}
+
+func NewDatabaseContext(ctx context.Context, path string, options ...Option) (Database, Stats, error) {
+	return load(ctx, path, options, (*loader).loadFile)
+}
+
+func NewDatabaseFromDir(dir string, options ...Option) (Database, error) {
+	database, _, err := load(context.Background(), dir, options, (*loader).loadDir)
+	return database, err
+}
+
+func load(ctx context.Context, root string, options []Option, fn func(*loader, string) error) (Database, Stats, error) {
+	start := time.Now()
+	if err := ctx.Err(); err != nil {
+		return Database{}, Stats{}, err
 	}
 
-	scanner := bufio.NewScanner(file)
-	var parser internal.Parser
-	for scanner.Scan() {
-		line := scanner.Text()
-		nodes, err := parser.Parse(line)
-		if err != nil {
-			return Database{}, fmt.Errorf("failed to parse database file: %w", err)
-		}
+	config := newConfig(options)
+	if config.cachePath != "" {
+		if database, err := readCache(config.cachePath, root, config); err == nil {
+			database.hostname = config.hostname
+			if database.altname != config.altname {
+				database.altname = config.altname
+				if err := database.indexNames(); err != nil {
+					return Database{}, Stats{}, err
+				}
+			}
+
+			if config.mergePolicy == MergeWarnOnConflict {
+				for _, conflict := range database.conflicts {
+					_, _ = config.mergePolicy.resolve(conflict, config.conflictHandler)
+				}
+			}
 
-		for _, node := range nodes {
-			if index, ok := database.names[node.Name]; ok {
-				database.nodes[index].mergeAttributes(node.Attributes)
-				continue
+			stats := Stats{
+				Nodes:      len(database.nodes),
+				Attributes: len(database.attrs),
+				Cached:     true,
+				Duration:   time.Since(start),
 			}
 
-			database.nodes = append(database.nodes, Node(node))
-			index := len(database.nodes) - 1
-			database.names[node.Name] = index
+			return database, stats, nil
 		}
 	}
 
-	database.indices = make(internal.Set, len(database.nodes))
-	for index, node := range database.nodes {
-		database.indices[index] = index
-		for key, value := range node.Attributes {
-			database.attrs[key] = append(database.attrs[key], index)
+	loader := newLoader(ctx, config, root)
+	if err := fn(loader, root); err != nil {
+		return Database{}, Stats{}, err
+	}
+
+	database, err := loader.database()
+	if err != nil {
+		return Database{}, Stats{}, err
+	}
 
-			if value != "" {
-				keyval := fmt.Sprintf("%s=%s", key, value)
-				database.attrvals[keyval] = append(database.attrvals[keyval], index)
-			}
-		}
+	if config.cachePath != "" {
+		_ = writeCache(config.cachePath, database)
 	}
 
-	if err := scanner.Err(); err != nil {
-		return Database{}, fmt.Errorf("failed to scan database file: %w", err)
+	stats := Stats{
+		Lines:      loader.lines,
+		Nodes:      len(database.nodes),
+		Attributes: len(database.attrs),
+		Merges:     loader.merges,
+		Duration:   time.Since(start),
 	}
 
-	return database, nil
+	return database, stats, nil
 }
 
 func (d Database) GetNodes() []Node {
 	return d.nodes
 }
 
+func (d Database) All() iter.Seq[Node] {
+	return slices.Values(d.nodes)
+}
+
+func (d Database) Names() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for _, node := range d.nodes {
+			if !yield(node.Name) {
+				return
+			}
+		}
+	}
+}
+
+func (d Database) GetNode(name string) (Node, bool) {
+	if index, ok := d.lookup(name); ok {
+		return d.nodes[index], true
+	}
+
+	return Node{}, false
+}
+
+func (d Database) GetAttrs() []string {
+	return slices.Sorted(maps.Keys(d.attrs))
+}
+
+func (d Database) Checksum() string {
+	return hex.EncodeToString(d.checksum[:])
+}
+
+func (d Database) Conflicts() []Conflict {
+	return d.conflicts
+}
+
 func (d Database) GetNodeAttr(name, attr string) (string, bool) {
-	if index, ok := d.names[name]; ok {
+	if index, ok := d.lookup(name); ok {
 		val, ok := d.nodes[index].Attributes[attr]
 		return val, ok
 	}
@@ -88,15 +172,77 @@ func (d Database) GetNodeAttr(name, attr string) (string, bool) {
 }
 
 func (d Database) Query(query string) ([]Node, error) {
-	tokens, err := internal.Tokenize(query)
+	set, err := d.evaluate(query)
 	if err != nil {
 		return nil, err
 	}
 
 	var nodes []Node
-	for _, index := range internal.ParseQuery(tokens).Evaluate(d.attrs, d.attrvals, d.indices) {
+	for _, index := range set {
 		nodes = append(nodes, d.nodes[index])
 	}
 
 	return nodes, nil
 }
+
+func (d Database) Count(query string) (int, error) {
+	set, err := d.evaluate(query)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(set), nil
+}
+
+func (d Database) Any(query string) (bool, error) {
+	count, err := d.Count(query)
+	return count > 0, err
+}
+
+func (d Database) TestQuery(name, query string) (bool, error) {
+	index, ok := d.lookup(name)
+	if !ok {
+		return false, fmt.Errorf("failed to test query %q: %w: %s", query, ErrNodeNotFound, name)
+	}
+
+	tokens, err := internal.Tokenize(query)
+	if err != nil {
+		return false, err
+	}
+
+	node := d.nodes[index]
+	attributes := make(map[string]string, len(node.Attributes))
+	maps.Copy(attributes, node.Attributes)
+	for _, term := range append([]string{node.Name}, d.altnames[index]...) {
+		if _, ok := d.attrs[term]; !ok {
+			attributes[term] = ""
+		}
+	}
+
+	return internal.ParseQuery(tokens).Matches(attributes), nil
+}
+
+func (d Database) QuerySeq(query string) iter.Seq2[Node, error] {
+	return func(yield func(Node, error) bool) {
+		set, err := d.evaluate(query)
+		if err != nil {
+			yield(Node{}, err)
+			return
+		}
+
+		for _, index := range set {
+			if !yield(d.nodes[index], nil) {
+				return
+			}
+		}
+	}
+}
+
+func (d Database) evaluate(query string) (internal.Set, error) {
+	tokens, err := internal.Tokenize(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return internal.ParseQuery(tokens).Evaluate(d.terms, d.attrvals, d.indices), nil
+}
@@ -1,9 +1,14 @@
 package libgenders_test
 
 import (
+	"bytes"
+	gocontext "context"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/ryanmoran/libgenders"
@@ -35,7 +40,126 @@ func testDatabase(t *testing.T, context spec.G, it spec.S) {
 			Expect(err).NotTo(HaveOccurred())
 		})
 
+		context("when a line is longer than the default scanner buffer", func() {
+			var names []string
+
+			it.Before(func() {
+				for i := range 20000 {
+					names = append(names, fmt.Sprintf("node%d", i))
+				}
+				Expect(os.WriteFile(path, []byte(strings.Join(names, ",")+" compute\n"), 0600)).To(Succeed())
+			})
+
+			it("loads every node on the line", func() {
+				database, err := libgenders.NewDatabase(path)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(database.GetNodes()).To(HaveLen(len(names)))
+			})
+		})
+
+		context("when lines end with a backslash", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(path, []byte("node[1-2] \\\n  compute,\\\n\tos=rhel9\nnode3 gpu\\"), 0600)).To(Succeed())
+			})
+
+			it("joins them with the following line", func() {
+				database, err := libgenders.NewDatabase(path)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(database.GetNodes()).To(Equal([]libgenders.Node{
+					{Name: "node1", Attributes: map[string]string{"compute": "", "os": "rhel9"}},
+					{Name: "node2", Attributes: map[string]string{"compute": "", "os": "rhel9"}},
+					{Name: "node3", Attributes: map[string]string{"gpu": ""}},
+				}))
+			})
+		})
+
+		context("when a hostrange is zero-padded", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(path, []byte("node[01-02] compute\nnode007 gpu\n"), 0600)).To(Succeed())
+			})
+
+			it("drops the padding from the range but keeps padded names", func() {
+				database, err := libgenders.NewDatabase(path)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(database.GetNodes()).To(Equal([]libgenders.Node{
+					{Name: "node1", Attributes: map[string]string{"compute": ""}},
+					{Name: "node2", Attributes: map[string]string{"compute": ""}},
+					{Name: "node007", Attributes: map[string]string{"gpu": ""}},
+				}))
+
+				var buffer bytes.Buffer
+				_, err = database.WriteTo(&buffer)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(buffer.String()).To(Equal("node[1-2] compute\nnode007 gpu\n"))
+			})
+		})
+
+		context("when a comment ends with a backslash", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(path, []byte("node1 a # dir C:\\\nnode2 b\n# see C:\\\nnode3 c\n"), 0600)).To(Succeed())
+			})
+
+			it("does not join it with the following line", func() {
+				database, err := libgenders.NewDatabase(path)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(database.GetNodes()).To(Equal([]libgenders.Node{
+					{Name: "node1", Attributes: map[string]string{"a": ""}},
+					{Name: "node2", Attributes: map[string]string{"b": ""}},
+					{Name: "node3", Attributes: map[string]string{"c": ""}},
+				}))
+			})
+		})
+
+		context("when the file has CRLF line endings or a byte order mark", func() {
+			it("strips them from names and values", func() {
+				for _, filename := range []string{"genders.crlf", "genders.bom"} {
+					database, err := libgenders.NewDatabase(filepath.Join("./testdata", filename))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(database.GetNodes()).To(Equal([]libgenders.Node{
+						{Name: "node1", Attributes: map[string]string{"attr1": "", "attr2": "val2"}},
+						{Name: "node2", Attributes: map[string]string{"attr1": "", "attr2": "val2"}},
+					}), filename)
+				}
+			})
+		})
+
+		context("when the file contains non-ASCII characters", func() {
+			it("loads them", func() {
+				database, err := libgenders.NewDatabase("./testdata/genders.non_ascii")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(database.GetNodes()).To(Equal([]libgenders.Node{
+					{Name: "node1", Attributes: map[string]string{"desc": "Größe", "rack": "架4"}},
+					{Name: "nøde2", Attributes: map[string]string{"attr1": ""}},
+				}))
+			})
+		})
+
 		context("failure cases", func() {
+			context("when the file contains invalid UTF-8", func() {
+				it("returns an error naming the line", func() {
+					_, err := libgenders.NewDatabase("./testdata/genders.invalid_utf8")
+					Expect(err).To(MatchError("failed to scan database file testdata/genders.invalid_utf8:2: invalid UTF-8"))
+				})
+			})
+
+			context("when the file contains control characters", func() {
+				it("returns an error naming the line", func() {
+					_, err := libgenders.NewDatabase("./testdata/genders.control_char")
+					Expect(err).To(MatchError("failed to scan database file testdata/genders.control_char:3: invalid control character U+0001"))
+				})
+			})
+
+			context("when a line exceeds the maximum length", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(path, []byte("node1 compute\nnode2 \\\n  compute,os=rhel9\n"), 0600)).To(Succeed())
+				})
+
+				it("returns an error naming the line", func() {
+					_, err := libgenders.NewDatabase(path, libgenders.WithMaxLineLength(16))
+					Expect(err).To(MatchError(fmt.Sprintf("failed to scan database file %s:2: line exceeds maximum length of 16 bytes", path)))
+				})
+			})
+
 			context("when the filepath does not exist", func() {
 				it("returns an error", func() {
 					_, err := libgenders.NewDatabase("no-such-file")
@@ -56,6 +180,206 @@ func testDatabase(t *testing.T, context spec.G, it spec.S) {
 		})
 	})
 
+	context("NewDatabaseContext", func() {
+		var path string
+
+		it.Before(func() {
+			path = filepath.Join(t.TempDir(), "genders")
+			Expect(os.WriteFile(path, []byte("node[1-2] attr1\nnode2 attr2=val2\n# comment\nnode3 \\\n  attr1\n"), 0600)).To(Succeed())
+		})
+
+		it("returns the database with load statistics", func() {
+			database, stats, err := libgenders.NewDatabaseContext(gocontext.Background(), path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(database.GetNodes()).To(HaveLen(3))
+			Expect(stats.Lines).To(Equal(5))
+			Expect(stats.Nodes).To(Equal(3))
+			Expect(stats.Attributes).To(Equal(2))
+			Expect(stats.Merges).To(Equal(1))
+			Expect(stats.Cached).To(BeFalse())
+			Expect(stats.Duration).To(BeNumerically(">", 0))
+		})
+
+		context("when the database is read from the cache", func() {
+			it("reports the cached load", func() {
+				cache := filepath.Join(t.TempDir(), "genders.cache")
+				_, _, err := libgenders.NewDatabaseContext(gocontext.Background(), path, libgenders.WithCache(cache))
+				Expect(err).NotTo(HaveOccurred())
+
+				_, stats, err := libgenders.NewDatabaseContext(gocontext.Background(), path, libgenders.WithCache(cache))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(stats.Cached).To(BeTrue())
+				Expect(stats.Nodes).To(Equal(3))
+				Expect(stats.Attributes).To(Equal(2))
+			})
+		})
+
+		context("failure cases", func() {
+			context("when the context is done before loading", func() {
+				it("returns an error", func() {
+					ctx, cancel := gocontext.WithCancel(gocontext.Background())
+					cancel()
+
+					_, _, err := libgenders.NewDatabaseContext(ctx, path)
+					Expect(err).To(MatchError(gocontext.Canceled))
+				})
+			})
+
+			context("when the context is done while loading", func() {
+				it("stops between lines and returns an error", func() {
+					ctx := &countdownContext{Context: gocontext.Background(), remaining: 3}
+
+					_, _, err := libgenders.NewDatabaseContext(ctx, path)
+					Expect(err).To(MatchError(gocontext.Canceled))
+					Expect(err).To(MatchError(fmt.Sprintf("failed to load database file %s:3: context canceled", path)))
+				})
+			})
+		})
+	})
+
+	context("NewDatabase with include directives", func() {
+		var dir string
+
+		it.Before(func() {
+			dir = t.TempDir()
+			Expect(os.Mkdir(filepath.Join(dir, "genders.d"), 0700)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "genders"), []byte("node[1-2] attr1\n#include genders.d/*.conf\nnode2 attr3=val3\n"), 0600)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "genders.d", "a.conf"), []byte("node1 attr2=valA\nnode3\n"), 0600)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "genders.d", "b.conf"), []byte("node1 attr2=valB\nnode3 attr1\n"), 0600)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "genders.d", "c.disabled"), []byte("node4 attr1\n"), 0600)).To(Succeed())
+		})
+
+		it("loads the included files in lexical order and merges their nodes", func() {
+			database, err := libgenders.NewDatabase(filepath.Join(dir, "genders"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(database.GetNodes()).To(Equal([]libgenders.Node{
+				{Name: "node1", Attributes: map[string]string{"attr1": "", "attr2": "valB"}},
+				{Name: "node2", Attributes: map[string]string{"attr1": "", "attr3": "val3"}},
+				{Name: "node3", Attributes: map[string]string{"attr1": ""}},
+			}))
+		})
+
+		context("when the include path is absolute", func() {
+			it.Before(func() {
+				content := fmt.Sprintf("#include %s\n", filepath.Join(dir, "genders.d", "a.conf"))
+				Expect(os.WriteFile(filepath.Join(dir, "genders"), []byte(content), 0600)).To(Succeed())
+			})
+
+			it("loads the included file", func() {
+				database, err := libgenders.NewDatabase(filepath.Join(dir, "genders"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(database.GetNodes()).To(Equal([]libgenders.Node{
+					{Name: "node1", Attributes: map[string]string{"attr2": "valA"}},
+					{Name: "node3"},
+				}))
+			})
+		})
+
+		context("when the include pattern matches a directory", func() {
+			it.Before(func() {
+				Expect(os.MkdirAll(filepath.Join(dir, "genders.d", "sub.conf"), 0700)).To(Succeed())
+			})
+
+			it("skips the directory", func() {
+				database, err := libgenders.NewDatabase(filepath.Join(dir, "genders"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(database.GetNodes()).To(HaveLen(3))
+			})
+		})
+
+		context("when the include pattern matches no files", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(dir, "genders"), []byte("node1 attr1\n#include genders.d/*.missing\n"), 0600)).To(Succeed())
+			})
+
+			it("loads the remaining entries", func() {
+				database, err := libgenders.NewDatabase(filepath.Join(dir, "genders"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(database.GetNodes()).To(HaveLen(1))
+			})
+		})
+
+		context("failure cases", func() {
+			context("when an included file cannot be parsed", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(dir, "genders.d", "b.conf"), []byte("node1 attr2\nnode[%%-%%] attr1\n"), 0600)).To(Succeed())
+				})
+
+				it("returns an error naming the file and line", func() {
+					_, err := libgenders.NewDatabase(filepath.Join(dir, "genders"))
+					Expect(err).To(MatchError(ContainSubstring(fmt.Sprintf("failed to parse database file %s:2", filepath.Join(dir, "genders.d", "b.conf")))))
+				})
+			})
+
+			context("when an included file does not exist", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(dir, "genders"), []byte("#include no-such-file\n"), 0600)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := libgenders.NewDatabase(filepath.Join(dir, "genders"))
+					Expect(err).To(MatchError(ContainSubstring(fmt.Sprintf("failed to include %s at %s:1", filepath.Join(dir, "no-such-file"), filepath.Join(dir, "genders")))))
+					Expect(err).To(MatchError(os.ErrNotExist))
+				})
+			})
+
+			context("when the files include each other", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(dir, "genders.d", "a.conf"), []byte("#include ../genders\n"), 0600)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := libgenders.NewDatabase(filepath.Join(dir, "genders"))
+					Expect(err).To(MatchError(ContainSubstring("include cycle")))
+				})
+			})
+		})
+	})
+
+	context("NewDatabaseFromDir", func() {
+		var dir string
+
+		it.Before(func() {
+			dir = t.TempDir()
+			Expect(os.WriteFile(filepath.Join(dir, "20-b.conf"), []byte("node1 attr2=valB\nnode2 attr1\n"), 0600)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "10-a.conf"), []byte("node1 attr1,attr2=valA\n"), 0600)).To(Succeed())
+			Expect(os.Mkdir(filepath.Join(dir, "subdir"), 0700)).To(Succeed())
+		})
+
+		it("loads every file in the directory in lexical order", func() {
+			database, err := libgenders.NewDatabaseFromDir(dir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(database.GetNodes()).To(Equal([]libgenders.Node{
+				{Name: "node1", Attributes: map[string]string{"attr1": "", "attr2": "valB"}},
+				{Name: "node2", Attributes: map[string]string{"attr1": ""}},
+			}))
+
+			nodes, err := database.Query("attr2=valB")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nodes).To(HaveLen(1))
+		})
+
+		context("failure cases", func() {
+			context("when the directory does not exist", func() {
+				it("returns an error", func() {
+					_, err := libgenders.NewDatabaseFromDir("no-such-dir")
+					Expect(err).To(MatchError(ContainSubstring("no-such-dir: no such file or directory")))
+				})
+			})
+
+			context("when a file cannot be parsed", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(dir, "30-c.conf"), []byte("node[%%-%%] attr1\n"), 0600)).To(Succeed())
+				})
+
+				it("returns an error naming the file", func() {
+					_, err := libgenders.NewDatabaseFromDir(dir)
+					Expect(err).To(MatchError(ContainSubstring(fmt.Sprintf("failed to parse database file %s:1", filepath.Join(dir, "30-c.conf")))))
+				})
+			})
+		})
+	})
+
 	context("GetNodes", func() {
 		var (
 			testdata = []string{
@@ -448,6 +772,101 @@ func testDatabase(t *testing.T, context spec.G, it spec.S) {
 		})
 	})
 
+	context("All", func() {
+		it("yields every node in database order", func() {
+			database, err := libgenders.NewDatabase("./testdata/genders.query_1_hostrange")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(slices.Collect(database.All())).To(Equal(database.GetNodes()))
+		})
+	})
+
+	context("Names", func() {
+		it("yields every node name in database order", func() {
+			database, err := libgenders.NewDatabase("./testdata/genders.nodes_only_many")
+			Expect(err).NotTo(HaveOccurred())
+
+			var names []string
+			for _, node := range database.GetNodes() {
+				names = append(names, node.Name)
+			}
+			Expect(slices.Collect(database.Names())).To(Equal(names))
+		})
+
+		it("stops when the consumer stops", func() {
+			database, err := libgenders.NewDatabase("./testdata/genders.nodes_only_many")
+			Expect(err).NotTo(HaveOccurred())
+
+			var names []string
+			for name := range database.Names() {
+				names = append(names, name)
+				break
+			}
+			Expect(names).To(HaveLen(1))
+		})
+	})
+
+	context("GetNode", func() {
+		var database libgenders.Database
+
+		it.Before(func() {
+			var err error
+			database, err = libgenders.NewDatabase("./testdata/genders.query_2_hostrange")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it("retrieves the node with the given name", func() {
+			node, found := database.GetNode("node2")
+			Expect(found).To(BeTrue())
+			Expect(node).To(Equal(libgenders.Node{
+				Name: "node2",
+				Attributes: map[string]string{
+					"attr1": "valA",
+					"attr2": "valB",
+					"attr3": "valD",
+					"attr4": "valI",
+				},
+			}))
+		})
+
+		context("when the node does not exist", func() {
+			it("returns false", func() {
+				_, found := database.GetNode("no-such-node")
+				Expect(found).To(BeFalse())
+			})
+		})
+	})
+
+	context("GetAttrs", func() {
+		it("returns the sorted list of attributes", func() {
+			database, err := libgenders.NewDatabase("./testdata/genders.query_1_hostrange")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(database.GetAttrs()).To(Equal([]string{
+				"attr1", "attr10", "attr2", "attr3", "attr4", "attr5", "attr6", "attr7", "attr8", "attr9",
+			}))
+		})
+	})
+
+	context("Checksum", func() {
+		it("changes when the contents of the file change", func() {
+			path := filepath.Join(t.TempDir(), "genders")
+			Expect(os.WriteFile(path, []byte("node1 attr1\n"), 0600)).To(Succeed())
+
+			first, err := libgenders.NewDatabase(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(first.Checksum()).To(HaveLen(64))
+
+			second, err := libgenders.NewDatabase(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(second.Checksum()).To(Equal(first.Checksum()))
+
+			Expect(os.WriteFile(path, []byte("node1 attr2\n"), 0600)).To(Succeed())
+
+			third, err := libgenders.NewDatabase(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(third.Checksum()).NotTo(Equal(first.Checksum()))
+		})
+	})
+
 	context("GetNodeAttr", func() {
 		var database libgenders.Database
 
@@ -826,6 +1245,37 @@ func testDatabase(t *testing.T, context spec.G, it spec.S) {
 			}
 		})
 
+		context("when the query contains quoted operands", func() {
+			it.Before(func() {
+				var err error
+				database, err = libgenders.NewDatabase("./testdata/genders.quoted_values")
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			data := map[string][]string{
+				`desc="GPU node, rack 4"`:            {"node1"},
+				`url="http://x/#frag" || plain=x\"y`: {"node1", "node2"},
+				`desc=node2\ in\ rack\ 4`:            {"node2"},
+				`~opts="a=b=c"`:                      {"node2"},
+			}
+
+			for _, query := range slices.Sorted(maps.Keys(data)) {
+				q, r := query, data[query]
+
+				it(fmt.Sprintf("finds the correct results for the query %q", q), func() {
+					nodes, err := database.Query(q)
+					Expect(err).NotTo(HaveOccurred())
+
+					var names []string
+					for _, node := range nodes {
+						names = append(names, node.Name)
+					}
+
+					Expect(names).To(Equal(r))
+				})
+			}
+		})
+
 		context("failure cases", func() {
 			context("when the query cannot be tokenized", func() {
 				it("returns an error", func() {
@@ -835,4 +1285,203 @@ func testDatabase(t *testing.T, context spec.G, it spec.S) {
 			})
 		})
 	})
+
+	context("Count", func() {
+		var database libgenders.Database
+
+		it.Before(func() {
+			var err error
+			database, err = libgenders.NewDatabase("./testdata/genders.query_1_hostrange")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it("counts the nodes matching the query", func() {
+			count, err := database.Count("attr3 && ~attr9")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(2))
+
+			count, err = database.Count("no-such-attr")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(0))
+		})
+
+		context("failure cases", func() {
+			context("when the query cannot be tokenized", func() {
+				it("returns an error", func() {
+					_, err := database.Count(") mismatched parentheses (")
+					Expect(err).To(MatchError(ContainSubstring("failed to tokenize query")))
+				})
+			})
+		})
+	})
+
+	context("Any", func() {
+		var database libgenders.Database
+
+		it.Before(func() {
+			var err error
+			database, err = libgenders.NewDatabase("./testdata/genders.query_1_hostrange")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it("reports whether any node matches the query", func() {
+			found, err := database.Any("attr5 && attr7")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+
+			found, err = database.Any("attr3 && attr5")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+
+		context("failure cases", func() {
+			context("when the query cannot be tokenized", func() {
+				it("returns an error", func() {
+					_, err := database.Any(") mismatched parentheses (")
+					Expect(err).To(MatchError(ContainSubstring("failed to tokenize query")))
+				})
+			})
+		})
+	})
+
+	context("TestQuery", func() {
+		var database libgenders.Database
+
+		it.Before(func() {
+			var err error
+			database, err = libgenders.NewDatabase("./testdata/genders.query_1_hostrange")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it("agrees with Query for every node", func() {
+			for _, query := range []string{"attr1", "attr3 && ~attr9", "(attr4=val4 || attr6=val6) -- attr7", "~attr2=val2", "no-such-attr"} {
+				matching := make(map[string]bool)
+				nodes, err := database.Query(query)
+				Expect(err).NotTo(HaveOccurred())
+				for _, node := range nodes {
+					matching[node.Name] = true
+				}
+
+				for name := range database.Names() {
+					ok, err := database.TestQuery(name, query)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(ok).To(Equal(matching[name]), fmt.Sprintf("%s %q", name, query))
+				}
+			}
+		})
+
+		context("failure cases", func() {
+			context("when the node does not exist", func() {
+				it("returns an error", func() {
+					_, err := database.TestQuery("no-such-node", "attr1")
+					Expect(err).To(MatchError(libgenders.ErrNodeNotFound))
+					Expect(err).To(MatchError(`failed to test query "attr1": node not found: no-such-node`))
+				})
+			})
+
+			context("when the query cannot be tokenized", func() {
+				it("returns an error", func() {
+					_, err := database.TestQuery("node1", ") mismatched parentheses (")
+					Expect(err).To(MatchError(ContainSubstring("failed to tokenize query")))
+				})
+			})
+		})
+	})
+
+	context("QuerySeq", func() {
+		var database libgenders.Database
+
+		it.Before(func() {
+			var err error
+			database, err = libgenders.NewDatabase("./testdata/genders.query_1_hostrange")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it("yields the nodes matching the query", func() {
+			expected, err := database.Query("attr3 || attr9")
+			Expect(err).NotTo(HaveOccurred())
+
+			var nodes []libgenders.Node
+			for node, err := range database.QuerySeq("attr3 || attr9") {
+				Expect(err).NotTo(HaveOccurred())
+				nodes = append(nodes, node)
+			}
+			Expect(nodes).To(Equal(expected))
+		})
+
+		it("stops when the consumer stops", func() {
+			var count int
+			for range database.QuerySeq("attr1") {
+				count++
+				break
+			}
+			Expect(count).To(Equal(1))
+		})
+
+		context("failure cases", func() {
+			context("when the query cannot be tokenized", func() {
+				it("yields the error", func() {
+					var errs []error
+					for _, err := range database.QuerySeq(") mismatched parentheses (") {
+						errs = append(errs, err)
+					}
+					Expect(errs).To(HaveLen(1))
+					Expect(errs[0]).To(MatchError(ContainSubstring("failed to tokenize query")))
+				})
+			})
+		})
+	})
+}
+
+func BenchmarkCount(b *testing.B) {
+	database := benchmarkDatabase(b)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := database.Count("compute && drain"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkQueryLen(b *testing.B) {
+	database := benchmarkDatabase(b)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		nodes, err := database.Query("compute && drain")
+		if err != nil {
+			b.Fatal(err)
+		}
+		_ = len(nodes)
+	}
+}
+
+func benchmarkDatabase(b *testing.B) libgenders.Database {
+	path := filepath.Join(b.TempDir(), "genders")
+	content := "node[1-20000] compute,os=rhel9,rack=r%3{i-1}\nnode[1-10000] drain\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		b.Fatal(err)
+	}
+
+	database, err := libgenders.NewDatabase(path)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return database
+}
+
+type countdownContext struct {
+	gocontext.Context
+	remaining int
+}
+
+func (c *countdownContext) Err() error {
+	if c.remaining == 0 {
+		return gocontext.Canceled
+	}
+	c.remaining--
+
+	return nil
 }
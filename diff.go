@@ -0,0 +1,138 @@
+package libgenders
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/ryanmoran/libgenders/internal"
+)
+
+type Changes struct {
+	Added   []string      `json:"added,omitempty"`
+	Removed []string      `json:"removed,omitempty"`
+	Nodes   []NodeChange  `json:"nodes,omitempty"`
+	Queries []QueryChange `json:"queries,omitempty"`
+}
+
+func (c Changes) IsEmpty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Nodes) == 0 && len(c.Queries) == 0
+}
+
+type NodeChange struct {
+	Name    string                 `json:"name"`
+	Added   map[string]string      `json:"added,omitempty"`
+	Removed map[string]string      `json:"removed,omitempty"`
+	Changed map[string]ValueChange `json:"changed,omitempty"`
+}
+
+type ValueChange struct {
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+type QueryChange struct {
+	Query   string   `json:"query"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+func Diff(a, b Database) Changes {
+	var changes Changes
+	for _, node := range a.GetNodes() {
+		if _, ok := b.names[node.Name]; !ok {
+			changes.Removed = append(changes.Removed, node.Name)
+		}
+	}
+
+	for _, node := range b.GetNodes() {
+		index, ok := a.names[node.Name]
+		if !ok {
+			changes.Added = append(changes.Added, node.Name)
+			continue
+		}
+
+		if change, ok := diffNode(a.nodes[index], node); ok {
+			changes.Nodes = append(changes.Nodes, change)
+		}
+	}
+
+	changes.Queries = append(changes.Queries, diffIndex(a, b, a.attrs, b.attrs)...)
+	changes.Queries = append(changes.Queries, diffIndex(a, b, a.attrvals, b.attrvals)...)
+	slices.SortFunc(changes.Queries, func(x, y QueryChange) int {
+		return strings.Compare(x.Query, y.Query)
+	})
+
+	return changes
+}
+
+func diffNode(a, b Node) (NodeChange, bool) {
+	change := NodeChange{Name: b.Name}
+	for key, value := range a.Attributes {
+		if _, ok := b.Attributes[key]; !ok {
+			if change.Removed == nil {
+				change.Removed = make(map[string]string)
+			}
+			change.Removed[key] = value
+		}
+	}
+
+	for key, value := range b.Attributes {
+		old, ok := a.Attributes[key]
+		switch {
+		case !ok:
+			if change.Added == nil {
+				change.Added = make(map[string]string)
+			}
+			change.Added[key] = value
+
+		case old != value:
+			if change.Changed == nil {
+				change.Changed = make(map[string]ValueChange)
+			}
+			change.Changed[key] = ValueChange{Old: old, New: value}
+		}
+	}
+
+	ok := len(change.Added) > 0 || len(change.Removed) > 0 || len(change.Changed) > 0
+	return change, ok
+}
+
+func diffIndex(a, b Database, left, right map[string]internal.Set) []QueryChange {
+	keys := maps.Clone(left)
+	maps.Copy(keys, right)
+
+	var changes []QueryChange
+	for _, key := range slices.Sorted(maps.Keys(keys)) {
+		before := a.nodeNames(left[key])
+		after := b.nodeNames(right[key])
+
+		change := QueryChange{Query: key}
+		for _, name := range slices.Sorted(maps.Keys(after)) {
+			if _, ok := before[name]; !ok {
+				change.Added = append(change.Added, name)
+			}
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(before)) {
+			if _, ok := after[name]; !ok {
+				change.Removed = append(change.Removed, name)
+			}
+		}
+
+		if len(change.Added) > 0 || len(change.Removed) > 0 {
+			changes = append(changes, change)
+		}
+	}
+
+	return changes
+}
+
+func (d Database) nodeNames(set internal.Set) map[string]struct{} {
+	names := make(map[string]struct{}, len(set))
+	for _, index := range set {
+		names[d.nodes[index].Name] = struct{}{}
+	}
+
+	return names
+}
@@ -0,0 +1,72 @@
+package libgenders_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ryanmoran/libgenders"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDiff(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		a, b libgenders.Database
+	)
+
+	it.Before(func() {
+		dir := t.TempDir()
+
+		path := filepath.Join(dir, "genders.a")
+		Expect(os.WriteFile(path, []byte("node[1-3] attr1,attr2=val2\nnode1 attr3=val3\nnode2 attr4\n"), 0600)).To(Succeed())
+
+		var err error
+		a, err = libgenders.NewDatabase(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		path = filepath.Join(dir, "genders.b")
+		Expect(os.WriteFile(path, []byte("node[2-4] attr1,attr2=val2\nnode2 attr2=other,attr5\n"), 0600)).To(Succeed())
+
+		b, err = libgenders.NewDatabase(path)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it("returns the changes between two databases", func() {
+		changes := libgenders.Diff(a, b)
+		Expect(changes.IsEmpty()).To(BeFalse())
+		Expect(changes.Added).To(Equal([]string{"node4"}))
+		Expect(changes.Removed).To(Equal([]string{"node1"}))
+		Expect(changes.Nodes).To(Equal([]libgenders.NodeChange{
+			{
+				Name:    "node2",
+				Added:   map[string]string{"attr5": ""},
+				Removed: map[string]string{"attr4": ""},
+				Changed: map[string]libgenders.ValueChange{
+					"attr2": {Old: "val2", New: "other"},
+				},
+			},
+		}))
+		Expect(changes.Queries).To(Equal([]libgenders.QueryChange{
+			{Query: "attr1", Added: []string{"node4"}, Removed: []string{"node1"}},
+			{Query: "attr2", Added: []string{"node4"}, Removed: []string{"node1"}},
+			{Query: "attr2=other", Added: []string{"node2"}},
+			{Query: "attr2=val2", Added: []string{"node4"}, Removed: []string{"node1", "node2"}},
+			{Query: "attr3", Removed: []string{"node1"}},
+			{Query: "attr3=val3", Removed: []string{"node1"}},
+			{Query: "attr4", Removed: []string{"node2"}},
+			{Query: "attr5", Added: []string{"node2"}},
+		}))
+	})
+
+	context("when the databases are the same", func() {
+		it("returns no changes", func() {
+			changes := libgenders.Diff(a, a)
+			Expect(changes.IsEmpty()).To(BeTrue())
+			Expect(changes).To(Equal(libgenders.Changes{}))
+		})
+	})
+}
@@ -5,11 +5,11 @@ go 1.25.0
 require (
 	github.com/onsi/gomega v1.42.1
 	github.com/sclevine/spec v1.4.0
+	go.yaml.in/yaml/v3 v3.0.4
 )
 
 require (
 	github.com/google/go-cmp v0.7.0 // indirect
-	go.yaml.in/yaml/v3 v3.0.4 // indirect
 	golang.org/x/net v0.56.0 // indirect
 	golang.org/x/text v0.38.0 // indirect
 )
@@ -0,0 +1,43 @@
+package libgenders
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+type Handle struct {
+	path     string
+	options  []Option
+	database atomic.Pointer[Database]
+}
+
+func NewHandle(path string, options ...Option) (*Handle, error) {
+	handle := &Handle{path: path, options: options}
+	if err := handle.Reload(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return handle, nil
+}
+
+func (h *Handle) Load() *Database {
+	return h.database.Load()
+}
+
+func (h *Handle) Reload(ctx context.Context) error {
+	database, _, err := NewDatabaseContext(ctx, h.path, h.options...)
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	h.Replace(database)
+	return nil
+}
+
+func (h *Handle) Replace(database Database) {
+	h.database.Store(&database)
+}
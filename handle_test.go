@@ -0,0 +1,110 @@
+package libgenders_test
+
+import (
+	gocontext "context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/ryanmoran/libgenders"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testHandle(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		path   string
+		handle *libgenders.Handle
+	)
+
+	it.Before(func() {
+		path = filepath.Join(t.TempDir(), "genders")
+		Expect(os.WriteFile(path, []byte("node1 attr1\n"), 0600)).To(Succeed())
+
+		var err error
+		handle, err = libgenders.NewHandle(path)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it("loads the database", func() {
+		Expect(handle.Load().GetNodes()).To(Equal([]libgenders.Node{
+			{Name: "node1", Attributes: map[string]string{"attr1": ""}},
+		}))
+	})
+
+	context("Reload", func() {
+		it("swaps in the database from disk", func() {
+			previous := handle.Load()
+			Expect(os.WriteFile(path, []byte("node1 attr1\nnode2 attr2\n"), 0600)).To(Succeed())
+
+			Expect(handle.Reload(gocontext.Background())).To(Succeed())
+			Expect(handle.Load().GetNodes()).To(HaveLen(2))
+			Expect(previous.GetNodes()).To(HaveLen(1))
+		})
+
+		it("never exposes a partially loaded database to concurrent readers", func() {
+			Expect(os.WriteFile(path, []byte("node[1-100] attr1\n"), 0600)).To(Succeed())
+
+			var wg sync.WaitGroup
+			for range 4 {
+				wg.Go(func() {
+					for range 100 {
+						count := len(handle.Load().GetNodes())
+						if count != 1 && count != 100 {
+							t.Errorf("unexpected node count %d", count)
+						}
+					}
+				})
+			}
+
+			Expect(handle.Reload(gocontext.Background())).To(Succeed())
+			wg.Wait()
+		})
+
+		context("failure cases", func() {
+			context("when the context is done", func() {
+				it("returns an error and keeps the previous database", func() {
+					Expect(os.WriteFile(path, []byte("node1 attr1\nnode2 attr2\n"), 0600)).To(Succeed())
+
+					ctx, cancel := gocontext.WithCancel(gocontext.Background())
+					cancel()
+
+					Expect(handle.Reload(ctx)).To(MatchError(gocontext.Canceled))
+					Expect(handle.Load().GetNodes()).To(HaveLen(1))
+				})
+			})
+
+			context("when the file cannot be parsed", func() {
+				it("returns an error and keeps the previous database", func() {
+					Expect(os.WriteFile(path, []byte("node[%%-%%] attr1\n"), 0600)).To(Succeed())
+
+					Expect(handle.Reload(gocontext.Background())).To(MatchError(ContainSubstring("failed to parse database file")))
+					Expect(handle.Load().GetNodes()).To(HaveLen(1))
+				})
+			})
+		})
+	})
+
+	context("Replace", func() {
+		it("swaps in the given database", func() {
+			database, err := libgenders.NewDatabase("./testdata/genders.query_1")
+			Expect(err).NotTo(HaveOccurred())
+
+			handle.Replace(database)
+			Expect(handle.Load().GetNodes()).To(HaveLen(8))
+		})
+	})
+
+	context("failure cases", func() {
+		context("when the file does not exist", func() {
+			it("returns an error", func() {
+				_, err := libgenders.NewHandle("no-such-file")
+				Expect(err).To(MatchError(ContainSubstring("no-such-file: no such file or directory")))
+			})
+		})
+	})
+}
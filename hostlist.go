@@ -0,0 +1,7 @@
+package libgenders
+
+import "github.com/ryanmoran/libgenders/internal"
+
+func CompressHostlist(names []string) string {
+	return internal.CompressHostlist(names)
+}
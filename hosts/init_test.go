@@ -0,0 +1,14 @@
+package hosts_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestHosts(t *testing.T) {
+	suite := spec.New(" libgenders/hosts", spec.Report(report.Terminal{}))
+	suite("Records", testRecords)
+	suite.Run(t)
+}
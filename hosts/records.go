@@ -0,0 +1,153 @@
+package hosts
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/ryanmoran/libgenders"
+)
+
+type Config struct {
+	Query      string
+	Attributes map[string]string
+	Domain     string
+}
+
+type Record struct {
+	Name    string
+	Address netip.Addr
+}
+
+func Records(database libgenders.Database, config Config) ([]Record, error) {
+	nodes := database.GetNodes()
+	if config.Query != "" {
+		var err error
+		nodes, err = database.Query(config.Query)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	attrs := slices.SortedFunc(maps.Keys(config.Attributes), func(a, b string) int {
+		if c := strings.Compare(config.Attributes[a], config.Attributes[b]); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	})
+
+	var (
+		records   []Record
+		errs      []error
+		names     = make(map[string]string)
+		addresses = make(map[netip.Addr]string)
+	)
+
+	for _, node := range nodes {
+		for _, attr := range attrs {
+			value, ok := node.Attributes[attr]
+			if !ok {
+				continue
+			}
+
+			address, err := netip.ParseAddr(value)
+			if err != nil || address.Zone() != "" {
+				errs = append(errs, fmt.Errorf("node %s attribute %s: invalid address %q", node.Name, attr, value))
+				continue
+			}
+
+			name := node.Name + config.Attributes[attr]
+			if previous, ok := names[name]; ok {
+				errs = append(errs, fmt.Errorf("duplicate name %s for addresses %s and %s", name, previous, address))
+				continue
+			}
+
+			if previous, ok := addresses[address]; ok {
+				errs = append(errs, fmt.Errorf("duplicate address %s for names %s and %s", address, previous, name))
+				continue
+			}
+
+			names[name] = address.String()
+			addresses[address] = name
+			records = append(records, Record{Name: name, Address: address})
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return records, nil
+}
+
+func WriteHosts(w io.Writer, records []Record, domain string) error {
+	var output strings.Builder
+	for _, record := range records {
+		if domain != "" {
+			fmt.Fprintf(&output, "%s\t%s.%s %s\n", record.Address, record.Name, domain, record.Name)
+			continue
+		}
+
+		fmt.Fprintf(&output, "%s\t%s\n", record.Address, record.Name)
+	}
+
+	_, err := io.WriteString(w, output.String())
+	return err
+}
+
+func WriteZone(w io.Writer, records []Record, domain string) error {
+	if domain == "" {
+		return errors.New("failed to write zone: missing domain")
+	}
+
+	var output strings.Builder
+	fmt.Fprintf(&output, "$ORIGIN %s.\n", strings.TrimSuffix(domain, "."))
+	for _, record := range records {
+		kind := "A"
+		if record.Address.Is6() {
+			kind = "AAAA"
+		}
+
+		fmt.Fprintf(&output, "%s\tIN\t%s\t%s\n", record.Name, kind, record.Address)
+	}
+
+	_, err := io.WriteString(w, output.String())
+	return err
+}
+
+func WriteReverseZone(w io.Writer, records []Record, domain string) error {
+	if domain == "" {
+		return errors.New("failed to write reverse zone: missing domain")
+	}
+
+	var output strings.Builder
+	for _, record := range records {
+		fmt.Fprintf(&output, "%s\tIN\tPTR\t%s.%s.\n", reverseName(record.Address), record.Name, strings.TrimSuffix(domain, "."))
+	}
+
+	_, err := io.WriteString(w, output.String())
+	return err
+}
+
+func reverseName(address netip.Addr) string {
+	var labels []string
+	if address.Is4() {
+		for _, octet := range address.As4() {
+			labels = append(labels, fmt.Sprint(octet))
+		}
+		slices.Reverse(labels)
+
+		return strings.Join(labels, ".") + ".in-addr.arpa."
+	}
+
+	for _, b := range address.As16() {
+		labels = append(labels, fmt.Sprintf("%x", b>>4), fmt.Sprintf("%x", b&0xf))
+	}
+	slices.Reverse(labels)
+
+	return strings.Join(labels, ".") + ".ip6.arpa."
+}
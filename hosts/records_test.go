@@ -0,0 +1,153 @@
+package hosts_test
+
+import (
+	"bytes"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ryanmoran/libgenders"
+	"github.com/ryanmoran/libgenders/hosts"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testRecords(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		path   string
+		config hosts.Config
+	)
+
+	load := func(content string) libgenders.Database {
+		Expect(os.WriteFile(path, []byte(content), 0600)).To(Succeed())
+
+		database, err := libgenders.NewDatabase(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		return database
+	}
+
+	it.Before(func() {
+		path = filepath.Join(t.TempDir(), "genders")
+		config = hosts.Config{
+			Query:      "compute",
+			Attributes: map[string]string{"ip": "", "bmc": "-bmc"},
+			Domain:     "example.com",
+		}
+	})
+
+	context("Records", func() {
+		it("returns a record for each mapped attribute of the matching nodes", func() {
+			database := load("node1 compute,ip=10.1.2.1,bmc=10.9.2.1\nnode2 compute,ip=10.1.2.2\nlogin1 ip=10.1.0.1\n")
+
+			records, err := hosts.Records(database, config)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(Equal([]hosts.Record{
+				{Name: "node1", Address: netip.MustParseAddr("10.1.2.1")},
+				{Name: "node1-bmc", Address: netip.MustParseAddr("10.9.2.1")},
+				{Name: "node2", Address: netip.MustParseAddr("10.1.2.2")},
+			}))
+		})
+
+		context("failure cases", func() {
+			context("when an address is invalid", func() {
+				it("returns an error", func() {
+					database := load("node1 compute,ip=10.1.2\n")
+
+					_, err := hosts.Records(database, config)
+					Expect(err).To(MatchError(`node node1 attribute ip: invalid address "10.1.2"`))
+				})
+			})
+
+			context("when an address has an IPv6 zone", func() {
+				it("returns an error", func() {
+					database := load("node1 compute,ip=fe80::1%%eth0\n")
+
+					_, err := hosts.Records(database, config)
+					Expect(err).To(MatchError(`node node1 attribute ip: invalid address "fe80::1%eth0"`))
+				})
+			})
+
+			context("when an address is assigned twice", func() {
+				it("reports every duplicate", func() {
+					database := load("node1 compute,ip=10.1.2.1,bmc=10.1.2.1\nnode2 compute,ip=10.1.2.1\n")
+
+					_, err := hosts.Records(database, config)
+					Expect(err).To(MatchError("duplicate address 10.1.2.1 for names node1 and node1-bmc\nduplicate address 10.1.2.1 for names node1 and node2"))
+				})
+			})
+
+			context("when a name is generated twice", func() {
+				it("returns an error", func() {
+					config.Attributes["ipmi"] = "-bmc"
+					database := load("node1 compute,bmc=10.9.2.1,ipmi=10.9.2.2\n")
+
+					_, err := hosts.Records(database, config)
+					Expect(err).To(MatchError("duplicate name node1-bmc for addresses 10.9.2.1 and 10.9.2.2"))
+				})
+			})
+
+			context("when the query is invalid", func() {
+				it("returns an error", func() {
+					config.Query = "(compute"
+					_, err := hosts.Records(load("node1 compute\n"), config)
+					Expect(err).To(MatchError(ContainSubstring("failed to tokenize query")))
+				})
+			})
+		})
+	})
+
+	context("writers", func() {
+		var records []hosts.Record
+
+		it.Before(func() {
+			records = []hosts.Record{
+				{Name: "node1", Address: netip.MustParseAddr("10.1.2.3")},
+				{Name: "node1-bmc", Address: netip.MustParseAddr("fd00::1")},
+			}
+		})
+
+		context("WriteHosts", func() {
+			it("writes /etc/hosts entries", func() {
+				var buffer bytes.Buffer
+				Expect(hosts.WriteHosts(&buffer, records, "example.com")).To(Succeed())
+				Expect(buffer.String()).To(Equal("10.1.2.3\tnode1.example.com node1\nfd00::1\tnode1-bmc.example.com node1-bmc\n"))
+			})
+
+			context("when no domain is given", func() {
+				it("writes short names", func() {
+					var buffer bytes.Buffer
+					Expect(hosts.WriteHosts(&buffer, records, "")).To(Succeed())
+					Expect(buffer.String()).To(Equal("10.1.2.3\tnode1\nfd00::1\tnode1-bmc\n"))
+				})
+			})
+		})
+
+		context("WriteZone", func() {
+			it("writes A and AAAA records", func() {
+				var buffer bytes.Buffer
+				Expect(hosts.WriteZone(&buffer, records, "example.com")).To(Succeed())
+				Expect(buffer.String()).To(Equal("$ORIGIN example.com.\nnode1\tIN\tA\t10.1.2.3\nnode1-bmc\tIN\tAAAA\tfd00::1\n"))
+			})
+
+			context("when no domain is given", func() {
+				it("returns an error", func() {
+					Expect(hosts.WriteZone(&bytes.Buffer{}, records, "")).To(MatchError("failed to write zone: missing domain"))
+				})
+			})
+		})
+
+		context("WriteReverseZone", func() {
+			it("writes PTR records", func() {
+				var buffer bytes.Buffer
+				Expect(hosts.WriteReverseZone(&buffer, records, "example.com.")).To(Succeed())
+				Expect(buffer.String()).To(Equal("3.2.1.10.in-addr.arpa.\tIN\tPTR\tnode1.example.com.\n" +
+					"1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.d.f.ip6.arpa.\tIN\tPTR\tnode1-bmc.example.com.\n"))
+			})
+		})
+	})
+}
@@ -0,0 +1,106 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ryanmoran/libgenders"
+)
+
+type Handler struct {
+	handle *libgenders.Handle
+	mux    *http.ServeMux
+}
+
+func NewHandler(handle *libgenders.Handle) Handler {
+	handler := Handler{
+		handle: handle,
+		mux:    http.NewServeMux(),
+	}
+
+	handler.mux.HandleFunc("GET /nodes", handler.nodes)
+	handler.mux.HandleFunc("GET /nodes/{name}", handler.node)
+	handler.mux.HandleFunc("GET /attrs", handler.attrs)
+	handler.mux.HandleFunc("GET /query", handler.query)
+
+	return handler
+}
+
+func (h Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	h.mux.ServeHTTP(w, req)
+}
+
+func (h Handler) nodes(w http.ResponseWriter, req *http.Request) {
+	database := h.handle.Load()
+	respond(w, req, database, http.StatusOK, database.GetNodes())
+}
+
+func (h Handler) node(w http.ResponseWriter, req *http.Request) {
+	database := h.handle.Load()
+
+	name := req.PathValue("name")
+	node, ok := database.GetNode(name)
+	if !ok {
+		respondError(w, http.StatusNotFound, fmt.Errorf("node %q not found", name))
+		return
+	}
+
+	respond(w, req, database, http.StatusOK, node)
+}
+
+func (h Handler) attrs(w http.ResponseWriter, req *http.Request) {
+	database := h.handle.Load()
+
+	attrs := database.GetAttrs()
+	if attrs == nil {
+		attrs = []string{}
+	}
+
+	respond(w, req, database, http.StatusOK, attrs)
+}
+
+func (h Handler) query(w http.ResponseWriter, req *http.Request) {
+	database := h.handle.Load()
+
+	query := req.URL.Query().Get("q")
+	if query == "" {
+		respondError(w, http.StatusBadRequest, fmt.Errorf("missing query parameter %q", "q"))
+		return
+	}
+
+	nodes, err := database.Query(query)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if nodes == nil {
+		nodes = []libgenders.Node{}
+	}
+
+	respond(w, req, database, http.StatusOK, nodes)
+}
+
+func respond(w http.ResponseWriter, req *http.Request, database *libgenders.Database, status int, body any) {
+	etag := fmt.Sprintf("%q", database.Checksum())
+	w.Header().Set("ETag", etag)
+
+	for match := range strings.SplitSeq(req.Header.Get("If-None-Match"), ",") {
+		if match = strings.TrimSpace(match); match == etag || match == "*" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func respondError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
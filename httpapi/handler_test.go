@@ -0,0 +1,157 @@
+package httpapi_test
+
+import (
+	gocontext "context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ryanmoran/libgenders"
+	"github.com/ryanmoran/libgenders/httpapi"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testHandler(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		path    string
+		handle  *libgenders.Handle
+		handler httpapi.Handler
+	)
+
+	it.Before(func() {
+		path = filepath.Join(t.TempDir(), "genders")
+		Expect(os.WriteFile(path, []byte("node[1-2] compute,os=rhel9\nnode2 gpu\n"), 0600)).To(Succeed())
+
+		var err error
+		handle, err = libgenders.NewHandle(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler = httpapi.NewHandler(handle)
+	})
+
+	serve := func(method, target string, headers ...string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, target, nil)
+		for i := 0; i+1 < len(headers); i += 2 {
+			request.Header.Set(headers[i], headers[i+1])
+		}
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+
+		return recorder
+	}
+
+	context("GET /nodes", func() {
+		it("returns every node", func() {
+			response := serve(http.MethodGet, "/nodes")
+			Expect(response.Code).To(Equal(http.StatusOK))
+			Expect(response.Header().Get("Content-Type")).To(Equal("application/json"))
+			Expect(response.Body.String()).To(MatchJSON(`[
+				{"name": "node1", "attributes": {"compute": "", "os": "rhel9"}},
+				{"name": "node2", "attributes": {"compute": "", "os": "rhel9", "gpu": ""}}
+			]`))
+		})
+	})
+
+	context("GET /nodes/{name}", func() {
+		it("returns the node", func() {
+			response := serve(http.MethodGet, "/nodes/node2")
+			Expect(response.Code).To(Equal(http.StatusOK))
+			Expect(response.Body.String()).To(MatchJSON(`{"name": "node2", "attributes": {"compute": "", "os": "rhel9", "gpu": ""}}`))
+		})
+
+		context("when the node does not exist", func() {
+			it("returns not found", func() {
+				response := serve(http.MethodGet, "/nodes/node9")
+				Expect(response.Code).To(Equal(http.StatusNotFound))
+				Expect(response.Body.String()).To(MatchJSON(`{"error": "node \"node9\" not found"}`))
+			})
+		})
+	})
+
+	context("GET /attrs", func() {
+		it("returns every attribute", func() {
+			response := serve(http.MethodGet, "/attrs")
+			Expect(response.Code).To(Equal(http.StatusOK))
+			Expect(response.Body.String()).To(MatchJSON(`["compute", "gpu", "os"]`))
+		})
+	})
+
+	context("GET /query", func() {
+		it("returns the nodes matching the query", func() {
+			response := serve(http.MethodGet, "/query?q=compute%20%26%26%20~gpu")
+			Expect(response.Code).To(Equal(http.StatusOK))
+			Expect(response.Body.String()).To(MatchJSON(`[{"name": "node1", "attributes": {"compute": "", "os": "rhel9"}}]`))
+		})
+
+		context("when no nodes match", func() {
+			it("returns an empty list", func() {
+				response := serve(http.MethodGet, "/query?q=no-such-attr")
+				Expect(response.Code).To(Equal(http.StatusOK))
+				Expect(response.Body.String()).To(MatchJSON(`[]`))
+			})
+		})
+
+		context("failure cases", func() {
+			context("when the query is missing", func() {
+				it("returns bad request", func() {
+					response := serve(http.MethodGet, "/query")
+					Expect(response.Code).To(Equal(http.StatusBadRequest))
+					Expect(response.Body.String()).To(MatchJSON(`{"error": "missing query parameter \"q\""}`))
+				})
+			})
+
+			context("when the query is invalid", func() {
+				it("returns bad request", func() {
+					response := serve(http.MethodGet, "/query?q=(compute")
+					Expect(response.Code).To(Equal(http.StatusBadRequest))
+					Expect(response.Body.String()).To(ContainSubstring("failed to tokenize query"))
+				})
+			})
+		})
+	})
+
+	context("ETag", func() {
+		it("returns the checksum of the database", func() {
+			response := serve(http.MethodGet, "/nodes")
+			Expect(response.Header().Get("ETag")).To(Equal(`"` + handle.Load().Checksum() + `"`))
+		})
+
+		context("when the ETag matches", func() {
+			it("returns not modified", func() {
+				etag := serve(http.MethodGet, "/attrs").Header().Get("ETag")
+
+				response := serve(http.MethodGet, "/attrs", "If-None-Match", etag)
+				Expect(response.Code).To(Equal(http.StatusNotModified))
+				Expect(response.Body.String()).To(BeEmpty())
+			})
+		})
+
+		context("when the database has been reloaded", func() {
+			it("returns the new contents", func() {
+				etag := serve(http.MethodGet, "/attrs").Header().Get("ETag")
+
+				Expect(os.WriteFile(path, []byte("node1 login\n"), 0600)).To(Succeed())
+				Expect(handle.Reload(gocontext.Background())).To(Succeed())
+
+				response := serve(http.MethodGet, "/attrs", "If-None-Match", etag)
+				Expect(response.Code).To(Equal(http.StatusOK))
+				Expect(response.Header().Get("ETag")).NotTo(Equal(etag))
+				Expect(response.Body.String()).To(MatchJSON(`["login"]`))
+			})
+		})
+	})
+
+	context("when the method is not GET", func() {
+		it("returns method not allowed", func() {
+			response := serve(http.MethodPost, "/nodes")
+			Expect(response.Code).To(Equal(http.StatusMethodNotAllowed))
+		})
+	})
+}
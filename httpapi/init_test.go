@@ -0,0 +1,14 @@
+package httpapi_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestHTTPAPI(t *testing.T) {
+	suite := spec.New(" libgenders/httpapi", spec.Report(report.Terminal{}))
+	suite("Handler", testHandler)
+	suite.Run(t)
+}
@@ -9,6 +9,16 @@ import (
 
 func TestLibgenders(t *testing.T) {
 	suite := spec.New(" libgenders", spec.Report(report.Terminal{}))
+	suite("Alias", testAlias)
+	suite("Cache", testCache)
 	suite("Database", testDatabase)
+	suite("Diff", testDiff)
+	suite("Handle", testHandle)
+	suite("JSON", testJSON)
+	suite("Local", testLocal)
+	suite("Merge", testMerge)
+	suite("Node", testNode)
+	suite("Watcher", testWatcher)
+	suite("Writer", testWriter)
 	suite.Run(t)
 }
@@ -0,0 +1,100 @@
+package internal
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type hostGroup struct {
+	prefix, suffix string
+	numbers        []int
+	literal        bool
+}
+
+func CompressHostlist(names []string) string {
+	var groups []*hostGroup
+	for _, name := range names {
+		prefix, digits, suffix, ok := splitHostname(name)
+		if !ok || (len(digits) > 1 && digits[0] == '0') {
+			groups = append(groups, &hostGroup{prefix: name, literal: true})
+			continue
+		}
+
+		number, err := strconv.Atoi(digits)
+		if err != nil {
+			groups = append(groups, &hostGroup{prefix: name, literal: true})
+			continue
+		}
+
+		index := slices.IndexFunc(groups, func(g *hostGroup) bool {
+			return !g.literal && g.prefix == prefix && g.suffix == suffix
+		})
+		if index < 0 {
+			groups = append(groups, &hostGroup{prefix: prefix, suffix: suffix})
+			index = len(groups) - 1
+		}
+
+		groups[index].numbers = append(groups[index].numbers, number)
+	}
+
+	hostlist := make([]string, 0, len(groups))
+	for _, group := range groups {
+		hostlist = append(hostlist, group.String())
+	}
+
+	return strings.Join(hostlist, ",")
+}
+
+func (g hostGroup) String() string {
+	if g.literal {
+		return g.prefix
+	}
+
+	slices.Sort(g.numbers)
+	g.numbers = slices.Compact(g.numbers)
+	if len(g.numbers) == 1 {
+		return fmt.Sprintf("%s%d%s", g.prefix, g.numbers[0], g.suffix)
+	}
+
+	var ranges []string
+	for i := 0; i < len(g.numbers); {
+		j := i
+		for j+1 < len(g.numbers) && g.numbers[j+1] == g.numbers[j]+1 {
+			j++
+		}
+
+		if i == j {
+			ranges = append(ranges, strconv.Itoa(g.numbers[i]))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%d-%d", g.numbers[i], g.numbers[j]))
+		}
+
+		i = j + 1
+	}
+
+	return fmt.Sprintf("%s[%s]%s", g.prefix, strings.Join(ranges, ","), g.suffix)
+}
+
+func splitHostname(name string) (string, string, string, bool) {
+	end := strings.LastIndexFunc(name, isDigit)
+	if end < 0 {
+		return "", "", "", false
+	}
+
+	start := end
+	for start > 0 && isDigit(rune(name[start-1])) {
+		start--
+	}
+
+	if start == 0 {
+		return "", "", "", false
+	}
+
+	return name[:start], name[start : end+1], name[end+1:], true
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
@@ -0,0 +1,57 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/ryanmoran/libgenders/internal"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testHostlist(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	context("CompressHostlist", func() {
+		it("compresses consecutive names into ranges", func() {
+			Expect(internal.CompressHostlist([]string{"node1", "node2", "node3", "node5", "node7", "node8"})).To(Equal("node[1-3,5,7-8]"))
+		})
+
+		it("sorts and deduplicates the numbers", func() {
+			Expect(internal.CompressHostlist([]string{"node3", "node1", "node2", "node1"})).To(Equal("node[1-3]"))
+		})
+
+		it("leaves a single name unchanged", func() {
+			Expect(internal.CompressHostlist([]string{"node1"})).To(Equal("node1"))
+		})
+
+		it("groups names by prefix and suffix in order of appearance", func() {
+			Expect(internal.CompressHostlist([]string{"gpu1", "node1-bmc", "gpu2", "node2-bmc", "login"})).To(Equal("gpu[1-2],node[1-2]-bmc,login"))
+		})
+
+		it("does not compress zero-padded names", func() {
+			Expect(internal.CompressHostlist([]string{"node08", "node09", "node10", "node11"})).To(Equal("node08,node09,node[10-11]"))
+		})
+
+		it("does not compress purely numeric names", func() {
+			Expect(internal.CompressHostlist([]string{"1", "2"})).To(Equal("1,2"))
+		})
+
+		it("round trips through the parser", func() {
+			names := []string{"node1", "node2", "node3", "node007", "node008", "rack1-node4", "login"}
+
+			nodes, err := internal.Parser{}.Parse(internal.CompressHostlist(names))
+			Expect(err).NotTo(HaveOccurred())
+
+			var parsed []string
+			for _, node := range nodes {
+				parsed = append(parsed, node.Name)
+			}
+			Expect(parsed).To(ConsistOf(names))
+		})
+
+		it("returns an empty string for no names", func() {
+			Expect(internal.CompressHostlist(nil)).To(BeEmpty())
+		})
+	})
+}
@@ -9,6 +9,7 @@ import (
 
 func TestInternal(t *testing.T) {
 	suite := spec.New(" libgenders/internal", spec.Report(report.Terminal{}))
+	suite("Hostlist", testHostlist)
 	suite("Parser", testParser)
 	suite("Query", testQuery)
 	suite("Scanner", testScanner)
@@ -5,6 +5,7 @@ import (
 	"maps"
 	"strconv"
 	"strings"
+	"unicode"
 )
 
 type Node struct {
@@ -14,47 +15,115 @@ type Node struct {
 
 type Parser struct{}
 
+type hostname struct {
+	name  string
+	index string
+}
+
 func (p Parser) Parse(line string) ([]Node, error) {
-	line, _, _ = strings.Cut(line, "#")
-	line = strings.TrimSpace(line)
-	if len(line) == 0 {
+	line = strings.TrimLeftFunc(line, unicode.IsSpace)
+	end := strings.IndexFunc(line, isDelimiter)
+	if end < 0 {
+		end = len(line)
+	}
+
+	field, rest := line[:end], strings.TrimLeftFunc(line[end:], unicode.IsSpace)
+	if len(field) == 0 {
 		return nil, nil
 	}
 
-	fields := strings.Fields(line)
-	names, err := p.parseNames(fields[0])
+	names, err := p.parseNames(field)
 	if err != nil {
 		return nil, err
 	}
 
 	var attributes map[string]string
-	if len(fields) > 1 {
-		attributes = p.parseAttrs(fields[1])
+	if len(rest) > 0 && rest[0] != '#' {
+		attributes, err = p.parseAttrs(rest)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	var nodes []Node
 	for _, name := range names {
-		attrs := p.copyAttrs(attributes, name)
 		nodes = append(nodes, Node{
-			Name:       name,
-			Attributes: attrs,
+			Name:       name.name,
+			Attributes: p.copyAttrs(attributes, name),
 		})
 	}
 
 	return nodes, nil
 }
 
-func (p Parser) parseAttrs(field string) map[string]string {
+func (p Parser) parseAttrs(field string) (map[string]string, error) {
 	attributes := make(map[string]string)
-	for _, attr := range strings.Split(field, ",") {
-		key, value, _ := strings.Cut(attr, "=")
+	for {
+		end := strings.IndexFunc(field, func(r rune) bool { return r == '=' || r == ',' || isDelimiter(r) })
+		if end < 0 {
+			attributes[field] = ""
+			return attributes, nil
+		}
+
+		key := field[:end]
+		field = field[end:]
+
+		var value string
+		if field[0] == '=' {
+			field = field[1:]
+			if strings.HasPrefix(field, `"`) {
+				var err error
+				value, field, err = p.parseQuoted(field)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse attribute %q: %w", key, err)
+				}
+			} else {
+				end := strings.IndexFunc(field, func(r rune) bool { return r == ',' || isDelimiter(r) })
+				if end < 0 {
+					end = len(field)
+				}
+				value, field = field[:end], field[end:]
+			}
+		}
 		attributes[key] = value
+
+		if len(field) == 0 || field[0] != ',' {
+			return attributes, nil
+		}
+		field = field[1:]
+	}
+}
+
+func (p Parser) parseQuoted(field string) (string, string, error) {
+	end := 1
+	for ; end < len(field) && field[end] != '"'; end++ {
+		if field[end] == '\\' {
+			end++
+		}
 	}
 
-	return attributes
+	if end >= len(field) {
+		return "", "", fmt.Errorf("unterminated quoted value %s", field)
+	}
+
+	value, err := strconv.Unquote(field[:end+1])
+	if err != nil {
+		return "", "", fmt.Errorf("invalid quoted value %s", field[:end+1])
+	}
+
+	rest := field[end+1:]
+	if len(rest) > 0 && rest[0] != ',' && !isDelimiter(rune(rest[0])) {
+		return "", "", fmt.Errorf("unexpected %q after quoted value %s", rest[0], field[:end+1])
+	}
+
+	return value, rest, nil
 }
 
-func (p Parser) copyAttrs(attributes map[string]string, name string) map[string]string {
+func isDelimiter(r rune) bool {
+	return r == '#' || unicode.IsSpace(r)
+}
+
+func (p Parser) copyAttrs(attributes map[string]string, name hostname) map[string]string {
 	var attrs map[string]string
 	if len(attributes) > 0 {
 		attrs = make(map[string]string)
@@ -63,14 +132,90 @@ func (p Parser) copyAttrs(attributes map[string]string, name string) map[string]
 
 	for key, val := range attrs {
 		if strings.Contains(val, "%") {
-			attrs[key] = strings.NewReplacer("%n", name, "%%", "%").Replace(val)
+			attrs[key] = p.substitute(val, name)
 		}
 	}
 
 	return attrs
 }
 
-func (p Parser) parseNames(field string) ([]string, error) {
+func (p Parser) substitute(value string, name hostname) string {
+	var builder strings.Builder
+	for len(value) > 0 {
+		before, after, ok := strings.Cut(value, "%")
+		builder.WriteString(before)
+		if !ok {
+			break
+		}
+
+		digits := len(after) - len(strings.TrimLeft(after, "0123456789"))
+		width, rest := after[:digits], after[digits:]
+
+		switch {
+		case width == "" && strings.HasPrefix(rest, "%"):
+			builder.WriteString("%")
+			value = rest[1:]
+			continue
+
+		case width == "" && strings.HasPrefix(rest, "n"):
+			builder.WriteString(name.name)
+			value = rest[1:]
+			continue
+
+		case strings.HasPrefix(rest, "i"), strings.HasPrefix(rest, "{"):
+			expression, remainder, found := "i", rest[1:], true
+			if rest[0] == '{' {
+				expression, remainder, found = strings.Cut(rest[1:], "}")
+			}
+
+			if index, ok := p.evaluateIndex(expression, name); found && ok {
+				padding, _ := strconv.Atoi(width)
+				builder.WriteString(fmt.Sprintf("%0*d", padding, index))
+				value = remainder
+				continue
+			}
+		}
+
+		builder.WriteString("%")
+		value = after
+	}
+
+	return builder.String()
+}
+
+func (p Parser) evaluateIndex(expression string, name hostname) (int, bool) {
+	rest, ok := strings.CutPrefix(expression, "i")
+	if !ok {
+		return 0, false
+	}
+
+	var offset int
+	if rest != "" {
+		sign := 1
+		switch rest[0] {
+		case '+':
+		case '-':
+			sign = -1
+		default:
+			return 0, false
+		}
+
+		value, err := strconv.Atoi(rest[1:])
+		if err != nil || value < 0 {
+			return 0, false
+		}
+		offset = sign * value
+	}
+
+	index, err := strconv.Atoi(name.index)
+	if err != nil || index+offset < 0 {
+		return 0, false
+	}
+
+	return index + offset, true
+}
+
+func (p Parser) parseNames(field string) ([]hostname, error) {
 	var (
 		name    string
 		inRange bool
@@ -100,7 +245,7 @@ func (p Parser) parseNames(field string) ([]string, error) {
 		fields = append(fields, name)
 	}
 
-	var names []string
+	var names []hostname
 	for _, f := range fields {
 		fieldNames, err := p.parseName(f)
 		if err != nil {
@@ -113,10 +258,16 @@ func (p Parser) parseNames(field string) ([]string, error) {
 	return names, nil
 }
 
-func (p Parser) parseName(field string) ([]string, error) {
+func (p Parser) parseName(field string) ([]hostname, error) {
 	parts := strings.FieldsFunc(field, func(c rune) bool { return c == '[' || c == ']' })
 	if len(parts) < 2 {
-		return parts, nil
+		var names []hostname
+		for _, part := range parts {
+			index := part[len(strings.TrimRight(part, "0123456789")):]
+			names = append(names, hostname{name: part, index: index})
+		}
+
+		return names, nil
 	}
 
 	prefix := parts[0]
@@ -132,9 +283,9 @@ func (p Parser) parseName(field string) ([]string, error) {
 		return nil, fmt.Errorf("failed to parse name %q: %w", field, err)
 	}
 
-	var names []string
+	var names []hostname
 	for _, index := range indices {
-		names = append(names, prefix+index+suffix)
+		names = append(names, hostname{name: prefix + index + suffix, index: index})
 	}
 
 	return names, nil
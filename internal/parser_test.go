@@ -153,6 +153,19 @@ func testParser(t *testing.T, context spec.G, it spec.S) {
 				})
 			})
 
+			context("when the range is zero-padded", func() {
+				it("drops the padding", func() {
+					nodes, err := parser.Parse("node[08-10,007]")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(nodes).To(Equal([]internal.Node{
+						{Name: "node8"},
+						{Name: "node9"},
+						{Name: "node10"},
+						{Name: "node7"},
+					}))
+				})
+			})
+
 			context("failure cases", func() {
 				context("when the first range value is non-numeric", func() {
 					it("returns an error", func() {
@@ -170,6 +183,125 @@ func testParser(t *testing.T, context spec.G, it spec.S) {
 			})
 		})
 
+		context("when attribute values contain substitutions", func() {
+			it("substitutes the node name and literal percent signs", func() {
+				nodes, err := parser.Parse("node[1-2] bmc=%n-ipmi,load=100%%")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nodes).To(Equal([]internal.Node{
+					{Name: "node1", Attributes: map[string]string{"bmc": "node1-ipmi", "load": "100%"}},
+					{Name: "node2", Attributes: map[string]string{"bmc": "node2-ipmi", "load": "100%"}},
+				}))
+			})
+
+			it("substitutes the range index", func() {
+				nodes, err := parser.Parse("node[09-10]-eth ip=10.0.0.%i")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nodes).To(Equal([]internal.Node{
+					{Name: "node9-eth", Attributes: map[string]string{"ip": "10.0.0.9"}},
+					{Name: "node10-eth", Attributes: map[string]string{"ip": "10.0.0.10"}},
+				}))
+			})
+
+			it("zero-pads and offsets the index", func() {
+				nodes, err := parser.Parse("node[1-2] rack=r%3i,ip=10.0.1.%{i+100},slot=%2{i-1}")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nodes).To(Equal([]internal.Node{
+					{Name: "node1", Attributes: map[string]string{"rack": "r001", "ip": "10.0.1.101", "slot": "00"}},
+					{Name: "node2", Attributes: map[string]string{"rack": "r002", "ip": "10.0.1.102", "slot": "01"}},
+				}))
+			})
+
+			it("uses the trailing number of names without a range", func() {
+				nodes, err := parser.Parse("node7 ip=10.0.0.%i")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nodes).To(Equal([]internal.Node{
+					{Name: "node7", Attributes: map[string]string{"ip": "10.0.0.7"}},
+				}))
+			})
+
+			it("leaves unknown sequences untouched", func() {
+				nodes, err := parser.Parse("node1 fmt=%s%5x")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nodes).To(Equal([]internal.Node{
+					{Name: "node1", Attributes: map[string]string{"fmt": "%s%5x"}},
+				}))
+			})
+
+			it("leaves substitutions that do not apply untouched", func() {
+				nodes, err := parser.Parse("login fmt=%i,x=%{x},open=%{i+1,mul=%{i*2}")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nodes).To(Equal([]internal.Node{
+					{Name: "login", Attributes: map[string]string{"fmt": "%i", "x": "%{x}", "open": "%{i+1", "mul": "%{i*2}"}},
+				}))
+			})
+
+			it("leaves offsets that go below zero untouched", func() {
+				nodes, err := parser.Parse("node[0-1] ip=10.0.0.%{i-1},p=%3{i-5}")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nodes).To(Equal([]internal.Node{
+					{Name: "node0", Attributes: map[string]string{"ip": "10.0.0.%{i-1}", "p": "%3{i-5}"}},
+					{Name: "node1", Attributes: map[string]string{"ip": "10.0.0.0", "p": "%3{i-5}"}},
+				}))
+			})
+		})
+
+		context("when attribute values are quoted", func() {
+			it("keeps commas, hashes, equals signs, and spaces in the value", func() {
+				nodes, err := parser.Parse(`node1 desc="GPU node, rack 4",url="http://x/#frag",opts="a=b",empty="" # comment`)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nodes).To(Equal([]internal.Node{
+					{
+						Name: "node1",
+						Attributes: map[string]string{
+							"desc":  "GPU node, rack 4",
+							"url":   "http://x/#frag",
+							"opts":  "a=b",
+							"empty": "",
+						},
+					},
+				}))
+			})
+
+			it("unescapes backslash sequences", func() {
+				nodes, err := parser.Parse(`node1 say="\"hi\"",path="C:\\tmp",tab="a\tb"`)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nodes).To(Equal([]internal.Node{
+					{Name: "node1", Attributes: map[string]string{"say": `"hi"`, "path": `C:\tmp`, "tab": "a\tb"}},
+				}))
+			})
+
+			it("treats quotes inside unquoted values literally", func() {
+				nodes, err := parser.Parse(`node1 a=x"y,b=\"z`)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nodes).To(Equal([]internal.Node{
+					{Name: "node1", Attributes: map[string]string{"a": `x"y`, "b": `\"z`}},
+				}))
+			})
+
+			context("failure cases", func() {
+				context("when the quoted value is unterminated", func() {
+					it("returns an error", func() {
+						_, err := parser.Parse(`node1 desc="GPU node, rack 4`)
+						Expect(err).To(MatchError(`failed to parse attribute "desc": unterminated quoted value "GPU node, rack 4`))
+					})
+				})
+
+				context("when the quoted value has an invalid escape", func() {
+					it("returns an error", func() {
+						_, err := parser.Parse(`node1 desc="a\qb"`)
+						Expect(err).To(MatchError(`failed to parse attribute "desc": invalid quoted value "a\qb"`))
+					})
+				})
+
+				context("when the quoted value is followed by other characters", func() {
+					it("returns an error", func() {
+						_, err := parser.Parse(`node1 desc="a"b,c`)
+						Expect(err).To(MatchError(`failed to parse attribute "desc": unexpected 'b' after quoted value "a"`))
+					})
+				})
+			})
+		})
+
 		context("when the line only specifies a node name", func() {
 			it("expands the range", func() {
 				nodes, err := parser.Parse("node1")
@@ -1,7 +1,10 @@
 package internal
 
+import "strings"
+
 type Query interface {
 	Evaluate(attrs, attrvals map[string]Set, indices Set) Set
+	Matches(attributes map[string]string) bool
 }
 
 func ParseQuery(tokens []Token) Query {
@@ -50,6 +53,17 @@ func (vq ValueQuery) Evaluate(attrs, attrvals map[string]Set, _ Set) Set {
 	return attrs[vq.Expression]
 }
 
+func (vq ValueQuery) Matches(attributes map[string]string) bool {
+	if key, value, ok := strings.Cut(vq.Expression, "="); ok && value != "" {
+		if current, found := attributes[key]; found && current == value {
+			return true
+		}
+	}
+
+	_, ok := attributes[vq.Expression]
+	return ok
+}
+
 type UnionQuery struct {
 	Left, Right Query
 }
@@ -60,6 +74,10 @@ func (uq UnionQuery) Evaluate(attrs, attrvals map[string]Set, indices Set) Set {
 	return left.Union(right)
 }
 
+func (uq UnionQuery) Matches(attributes map[string]string) bool {
+	return uq.Left.Matches(attributes) || uq.Right.Matches(attributes)
+}
+
 type IntersectionQuery struct {
 	Left, Right Query
 }
@@ -70,6 +88,10 @@ func (iq IntersectionQuery) Evaluate(attrs, attrvals map[string]Set, indices Set
 	return left.Intersection(right)
 }
 
+func (iq IntersectionQuery) Matches(attributes map[string]string) bool {
+	return iq.Left.Matches(attributes) && iq.Right.Matches(attributes)
+}
+
 type DifferenceQuery struct {
 	Left, Right Query
 }
@@ -80,6 +102,10 @@ func (dq DifferenceQuery) Evaluate(attrs, attrvals map[string]Set, indices Set)
 	return left.Difference(right)
 }
 
+func (dq DifferenceQuery) Matches(attributes map[string]string) bool {
+	return dq.Left.Matches(attributes) && !dq.Right.Matches(attributes)
+}
+
 type ComplementQuery struct {
 	Query Query
 }
@@ -88,3 +114,7 @@ func (cq ComplementQuery) Evaluate(attrs, attrvals map[string]Set, indices Set)
 	query := cq.Query.Evaluate(attrs, attrvals, indices)
 	return indices.Difference(query)
 }
+
+func (cq ComplementQuery) Matches(attributes map[string]string) bool {
+	return !cq.Query.Matches(attributes)
+}
@@ -33,6 +33,9 @@ func testQuery(t *testing.T, context spec.G, it spec.S) {
 			"attr8=val8":   {0, 2, 4, 6},
 		}
 		indices = internal.Set{0, 1, 2, 3, 4, 5, 6, 7}
+
+		node0 = map[string]string{"attr1": "", "attr2": "val2", "attr3": "", "attr4": "val4", "attr7": "", "attr8": "val8"}
+		node1 = map[string]string{"attr1": "", "attr2": "val2", "attr3": "", "attr4": "val4", "attr9": "", "attr10": "val10"}
 	)
 
 	context("ParseQuery", func() {
@@ -150,6 +153,17 @@ func testQuery(t *testing.T, context spec.G, it spec.S) {
 			result := query.Evaluate(attrs, attrvals, indices)
 			Expect(result).To(Equal(internal.Set{0, 1, 2, 3}))
 		})
+
+		it("matches a node with the attribute", func() {
+			Expect(internal.ValueQuery{Expression: "attr7"}.Matches(node0)).To(BeTrue())
+			Expect(internal.ValueQuery{Expression: "attr7"}.Matches(node1)).To(BeFalse())
+		})
+
+		it("matches a node with the attribute value", func() {
+			Expect(internal.ValueQuery{Expression: "attr8=val8"}.Matches(node0)).To(BeTrue())
+			Expect(internal.ValueQuery{Expression: "attr2=val3"}.Matches(node0)).To(BeFalse())
+			Expect(internal.ValueQuery{Expression: "attr1="}.Matches(node0)).To(BeFalse())
+		})
 	})
 
 	context("UnionQuery", func() {
@@ -162,6 +176,16 @@ func testQuery(t *testing.T, context spec.G, it spec.S) {
 			result := query.Evaluate(attrs, attrvals, indices)
 			Expect(result).To(Equal(internal.Set{0, 1, 2, 3, 4, 6}))
 		})
+
+		it("matches a node matching either side", func() {
+			query := internal.UnionQuery{
+				Left:  internal.ValueQuery{Expression: "attr7"},
+				Right: internal.ValueQuery{Expression: "attr5"},
+			}
+
+			Expect(query.Matches(node0)).To(BeTrue())
+			Expect(query.Matches(node1)).To(BeFalse())
+		})
 	})
 
 	context("IntersectionQuery", func() {
@@ -174,6 +198,16 @@ func testQuery(t *testing.T, context spec.G, it spec.S) {
 			result := query.Evaluate(attrs, attrvals, indices)
 			Expect(result).To(Equal(internal.Set{0, 2}))
 		})
+
+		it("matches a node matching both sides", func() {
+			query := internal.IntersectionQuery{
+				Left:  internal.ValueQuery{Expression: "attr4"},
+				Right: internal.ValueQuery{Expression: "attr8=val8"},
+			}
+
+			Expect(query.Matches(node0)).To(BeTrue())
+			Expect(query.Matches(node1)).To(BeFalse())
+		})
 	})
 
 	context("DifferenceQuery", func() {
@@ -186,6 +220,16 @@ func testQuery(t *testing.T, context spec.G, it spec.S) {
 			result := query.Evaluate(attrs, attrvals, indices)
 			Expect(result).To(Equal(internal.Set{1, 3}))
 		})
+
+		it("matches a node matching only the left side", func() {
+			query := internal.DifferenceQuery{
+				Left:  internal.ValueQuery{Expression: "attr4"},
+				Right: internal.ValueQuery{Expression: "attr8=val8"},
+			}
+
+			Expect(query.Matches(node0)).To(BeFalse())
+			Expect(query.Matches(node1)).To(BeTrue())
+		})
 	})
 
 	context("ComplementQuery", func() {
@@ -197,5 +241,14 @@ func testQuery(t *testing.T, context spec.G, it spec.S) {
 			result := query.Evaluate(attrs, attrvals, indices)
 			Expect(result).To(Equal(internal.Set{1, 3, 5, 7}))
 		})
+
+		it("matches a node not matching the query", func() {
+			query := internal.ComplementQuery{
+				Query: internal.ValueQuery{Expression: "attr8=val8"},
+			}
+
+			Expect(query.Matches(node0)).To(BeFalse())
+			Expect(query.Matches(node1)).To(BeTrue())
+		})
 	})
 }
@@ -1,6 +1,9 @@
 package internal
 
-import "strings"
+import (
+	"errors"
+	"strings"
+)
 
 type Scanner struct {
 	reader *strings.Reader
@@ -26,6 +29,25 @@ func (s Scanner) Next() (string, error) {
 		return string(buffer), nil
 	}
 
+	switch buffer[0] {
+	case '\\':
+		if n < 2 {
+			return "", errors.New("trailing backslash")
+		}
+
+		return string(buffer), nil
+
+	case '"':
+		if n == 2 {
+			err = s.reader.UnreadByte()
+			if err != nil {
+				return "", err
+			}
+		}
+
+		return s.quoted()
+	}
+
 	if n == 2 {
 		err = s.reader.UnreadByte()
 		if err != nil {
@@ -35,3 +57,28 @@ func (s Scanner) Next() (string, error) {
 
 	return string(buffer[:1]), nil
 }
+
+func (s Scanner) quoted() (string, error) {
+	var builder strings.Builder
+	builder.WriteByte('"')
+
+	for {
+		c, err := s.reader.ReadByte()
+		if err != nil {
+			return "", errors.New("unterminated quoted operand")
+		}
+		builder.WriteByte(c)
+
+		switch c {
+		case '"':
+			return builder.String(), nil
+
+		case '\\':
+			c, err := s.reader.ReadByte()
+			if err != nil {
+				return "", errors.New("unterminated quoted operand")
+			}
+			builder.WriteByte(c)
+		}
+	}
+}
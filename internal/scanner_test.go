@@ -36,5 +36,35 @@ func testScanner(t *testing.T, context spec.G, it spec.S) {
 
 			Expect(parts).To(Equal([]string{"w", " ", "&&", " ", "x", " ", "||", " ", "y", " ", "--", " ", "z"}))
 		})
+
+		it("returns quoted sections and escaped characters whole", func() {
+			var parts []string
+			scanner := internal.NewScanner(`os="rhel--9 \"x\"" && a\&&b`)
+			for scanner.Len() > 0 {
+				part, err := scanner.Next()
+				Expect(err).NotTo(HaveOccurred())
+				parts = append(parts, part)
+			}
+
+			Expect(parts).To(Equal([]string{"o", "s", "=", `"rhel--9 \"x\""`, " ", "&&", " ", "a", `\&`, "&", "b"}))
+		})
+
+		context("failure cases", func() {
+			context("when a quoted section is unterminated", func() {
+				it("returns an error", func() {
+					scanner := internal.NewScanner(`"rhel`)
+					_, err := scanner.Next()
+					Expect(err).To(MatchError("unterminated quoted operand"))
+				})
+			})
+
+			context("when the query ends with a backslash", func() {
+				it("returns an error", func() {
+					scanner := internal.NewScanner(`\`)
+					_, err := scanner.Next()
+					Expect(err).To(MatchError("trailing backslash"))
+				})
+			})
+		})
 	})
 }
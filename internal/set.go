@@ -80,6 +80,11 @@ func (s Set) Difference(o Set) Set {
 	i := 0
 	j := 0
 	for i < len(s) {
+		if j < len(o) && o[j] < s[i] {
+			j++
+			continue
+		}
+
 		if j < len(o) && s[i] == o[j] {
 			i++
 			j++
@@ -66,6 +66,13 @@ func testSet(t *testing.T, context spec.G, it spec.S) {
 			Expect(left.Difference(right)).To(Equal(internal.Set([]int{1, 2})))
 		})
 
+		it("returns a new set that is the difference when the right set has smaller elements", func() {
+			left := internal.Set([]int{0, 2, 4, 6})
+			right := internal.Set([]int{0, 1, 2, 3})
+
+			Expect(left.Difference(right)).To(Equal(internal.Set([]int{4, 6})))
+		})
+
 		it("returns a new set that is the difference of the unequal-length sets", func() {
 			left := internal.Set([]int{1, 2, 3})
 			right := internal.Set([]int{3, 4, 5, 6, 7})
@@ -3,6 +3,7 @@ package internal
 import (
 	"fmt"
 	"slices"
+	"strconv"
 	"strings"
 )
 
@@ -41,12 +42,12 @@ func Tokenize(query string) ([]Token, error) {
 	for scanner.Len() > 0 {
 		s, err := scanner.Next()
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("failed to tokenize query %q: %w", query, err)
 		}
 
 		var token Token
 		switch s {
-		case " ":
+		case " ", "\t", "\n", "\r":
 			token = NewToken(SpaceTokenKind, s)
 
 		case "||":
@@ -68,7 +69,20 @@ func Tokenize(query string) ([]Token, error) {
 			token = NewToken(RightParenTokenKind, s)
 
 		default:
-			buffer.WriteString(s)
+			switch s[0] {
+			case '\\':
+				buffer.WriteString(s[1:])
+
+			case '"':
+				operand, err := strconv.Unquote(s)
+				if err != nil {
+					return nil, fmt.Errorf("failed to tokenize query %q: invalid quoted operand %s", query, s)
+				}
+				buffer.WriteString(operand)
+
+			default:
+				buffer.WriteString(s)
+			}
 			continue
 		}
 
@@ -85,7 +85,68 @@ func testToken(t *testing.T, context spec.G, it spec.S) {
 			}))
 		})
 
+		it("parses quoted operands", func() {
+			tokens, err := internal.Tokenize(`os="rhel--9" || desc="a b" || "~(x)"`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tokens).To(Equal([]internal.Token{
+				{Kind: internal.UnionTokenKind, Text: "||"},
+				{Kind: internal.ValueTokenKind, Text: "~(x)"},
+				{Kind: internal.UnionTokenKind, Text: "||"},
+				{Kind: internal.ValueTokenKind, Text: "desc=a b"},
+				{Kind: internal.ValueTokenKind, Text: "os=rhel--9"},
+			}))
+		})
+
+		it("parses backslash escapes in quoted operands", func() {
+			tokens, err := internal.Tokenize(`say="\"hi\"\tthere"`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tokens).To(Equal([]internal.Token{
+				{Kind: internal.ValueTokenKind, Text: "say=\"hi\"\tthere"},
+			}))
+		})
+
+		it("parses backslash escapes in unquoted operands", func() {
+			tokens, err := internal.Tokenize(`os=rhel\-\-9 && desc=a\ b\~`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tokens).To(Equal([]internal.Token{
+				{Kind: internal.IntersectionTokenKind, Text: "&&"},
+				{Kind: internal.ValueTokenKind, Text: "desc=a b~"},
+				{Kind: internal.ValueTokenKind, Text: "os=rhel--9"},
+			}))
+		})
+
+		it("treats tabs and newlines as whitespace", func() {
+			tokens, err := internal.Tokenize("attr1\t&&\nattr2\r\n")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tokens).To(Equal([]internal.Token{
+				{Kind: internal.IntersectionTokenKind, Text: "&&"},
+				{Kind: internal.ValueTokenKind, Text: "attr2"},
+				{Kind: internal.ValueTokenKind, Text: "attr1"},
+			}))
+		})
+
 		context("failure cases", func() {
+			context("when a quoted operand is unterminated", func() {
+				it("returns an error", func() {
+					_, err := internal.Tokenize(`os="rhel--9`)
+					Expect(err).To(MatchError(`failed to tokenize query "os=\"rhel--9": unterminated quoted operand`))
+				})
+			})
+
+			context("when a quoted operand has an invalid escape", func() {
+				it("returns an error", func() {
+					_, err := internal.Tokenize(`os="rhel\q"`)
+					Expect(err).To(MatchError(`failed to tokenize query "os=\"rhel\\q\"": invalid quoted operand "rhel\q"`))
+				})
+			})
+
+			context("when the query ends with a backslash", func() {
+				it("returns an error", func() {
+					_, err := internal.Tokenize(`attr1\`)
+					Expect(err).To(MatchError(`failed to tokenize query "attr1\\": trailing backslash`))
+				})
+			})
+
 			context("when the left parentheses in the query are mismatched", func() {
 				it("returns an error", func() {
 					_, err := internal.Tokenize("((attr1 && ~attr3) || attr1 -- attr5)) && attr7")
@@ -0,0 +1,139 @@
+package libgenders
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/ryanmoran/libgenders/internal"
+	"go.yaml.in/yaml/v3"
+)
+
+type Document struct {
+	Nodes []Node              `json:"nodes" yaml:"nodes"`
+	Index map[string][]string `json:"index,omitempty" yaml:"index,omitempty"`
+}
+
+func (d Database) Document(index bool) Document {
+	document := Document{Nodes: d.nodes}
+	if document.Nodes == nil {
+		document.Nodes = []Node{}
+	}
+
+	if index {
+		document.Index = make(map[string][]string, len(d.attrs)+len(d.attrvals))
+		for _, sets := range []map[string]internal.Set{d.attrs, d.attrvals} {
+			for key, set := range sets {
+				for _, i := range set {
+					document.Index[key] = append(document.Index[key], d.nodes[i].Name)
+				}
+			}
+		}
+	}
+
+	return document
+}
+
+func (doc Document) Database() (Database, error) {
+	loader := newLoader(context.Background(), newConfig(nil), "")
+	for i, node := range doc.Nodes {
+		if node.Name == "" {
+			return Database{}, fmt.Errorf("failed to decode database: node %d has no name", i)
+		}
+
+		if strings.ContainsFunc(node.Name, func(r rune) bool { return strings.ContainsRune(",#[]", r) || unicode.IsSpace(r) }) {
+			return Database{}, fmt.Errorf("failed to decode database: invalid node name %q", node.Name)
+		}
+
+		for _, key := range slices.Sorted(maps.Keys(node.Attributes)) {
+			if strings.ContainsFunc(key, func(r rune) bool { return strings.ContainsRune("=,#", r) || unicode.IsSpace(r) }) {
+				return Database{}, fmt.Errorf("failed to decode database: invalid attribute %q of node %s", key, node.Name)
+			}
+		}
+
+		if _, ok := loader.names[node.Name]; ok {
+			return Database{}, fmt.Errorf("failed to decode database: duplicate node %q", node.Name)
+		}
+
+		if err := loader.merge([]internal.Node{internal.Node(node)}, "", i+1); err != nil {
+			return Database{}, err
+		}
+	}
+
+	return loader.database()
+}
+
+func (d Database) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Document(false))
+}
+
+func (d *Database) UnmarshalJSON(data []byte) error {
+	var document Document
+	if err := json.Unmarshal(data, &document); err != nil {
+		return err
+	}
+
+	database, err := document.Database()
+	if err != nil {
+		return err
+	}
+
+	*d = database
+	return nil
+}
+
+func (d Database) MarshalYAML() (any, error) {
+	return d.Document(false), nil
+}
+
+func (d *Database) UnmarshalYAML(value *yaml.Node) error {
+	var document Document
+	if err := value.Decode(&document); err != nil {
+		return err
+	}
+
+	database, err := document.Database()
+	if err != nil {
+		return err
+	}
+
+	*d = database
+	return nil
+}
+
+type documentNode struct {
+	Name       string            `json:"name" yaml:"name"`
+	Attributes map[string]string `json:"attributes,omitempty" yaml:"attributes,omitempty"`
+}
+
+func (n Node) MarshalJSON() ([]byte, error) {
+	return json.Marshal(documentNode(n))
+}
+
+func (n *Node) UnmarshalJSON(data []byte) error {
+	var node documentNode
+	if err := json.Unmarshal(data, &node); err != nil {
+		return err
+	}
+
+	*n = Node(node)
+	return nil
+}
+
+func (n Node) MarshalYAML() (any, error) {
+	return documentNode(n), nil
+}
+
+func (n *Node) UnmarshalYAML(value *yaml.Node) error {
+	var node documentNode
+	if err := value.Decode(&node); err != nil {
+		return err
+	}
+
+	*n = Node(node)
+	return nil
+}
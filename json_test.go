@@ -0,0 +1,165 @@
+package libgenders_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/ryanmoran/libgenders"
+	"github.com/sclevine/spec"
+	"go.yaml.in/yaml/v3"
+
+	. "github.com/onsi/gomega"
+)
+
+func testJSON(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		database libgenders.Database
+	)
+
+	it.Before(func() {
+		var err error
+		database, err = libgenders.NewDatabase("./testdata/genders.subst_nodename_hostrange")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	context("MarshalJSON", func() {
+		it("encodes the nodes and their attributes", func() {
+			data, err := json.Marshal(database)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(data).To(MatchJSON(`{
+				"nodes": [
+					{"name": "node1", "attributes": {"attr1": "", "attr2": "val2", "attr3": "node1"}},
+					{"name": "node2", "attributes": {"attr1": "", "attr2": "val2", "attr3": "node2"}}
+				]
+			}`))
+		})
+
+		it("omits empty attributes", func() {
+			data, err := json.Marshal(libgenders.Node{Name: "node1"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(data).To(MatchJSON(`{"name": "node1"}`))
+		})
+	})
+
+	context("Document", func() {
+		it("includes the attribute index when requested", func() {
+			data, err := json.Marshal(database.Document(true))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(data).To(MatchJSON(`{
+				"nodes": [
+					{"name": "node1", "attributes": {"attr1": "", "attr2": "val2", "attr3": "node1"}},
+					{"name": "node2", "attributes": {"attr1": "", "attr2": "val2", "attr3": "node2"}}
+				],
+				"index": {
+					"attr1": ["node1", "node2"],
+					"attr2": ["node1", "node2"],
+					"attr2=val2": ["node1", "node2"],
+					"attr3": ["node1", "node2"],
+					"attr3=node1": ["node1"],
+					"attr3=node2": ["node2"]
+				}
+			}`))
+		})
+	})
+
+	context("UnmarshalJSON", func() {
+		it("decodes the database and rebuilds its index", func() {
+			var decoded libgenders.Database
+			Expect(json.Unmarshal([]byte(`{
+				"nodes": [
+					{"name": "node1", "attributes": {"attr1": "", "attr2": "val2"}},
+					{"name": "node2"}
+				],
+				"index": {"ignored": ["node2"]}
+			}`), &decoded)).To(Succeed())
+
+			Expect(decoded.GetNodes()).To(Equal([]libgenders.Node{
+				{Name: "node1", Attributes: map[string]string{"attr1": "", "attr2": "val2"}},
+				{Name: "node2"},
+			}))
+
+			nodes, err := decoded.Query("attr2=val2")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nodes).To(HaveLen(1))
+		})
+
+		it("round trips the database", func() {
+			data, err := json.Marshal(database)
+			Expect(err).NotTo(HaveOccurred())
+
+			var decoded libgenders.Database
+			Expect(json.Unmarshal(data, &decoded)).To(Succeed())
+			Expect(decoded.GetNodes()).To(Equal(database.GetNodes()))
+		})
+
+		context("failure cases", func() {
+			context("when a node has no name", func() {
+				it("returns an error", func() {
+					var decoded libgenders.Database
+					err := json.Unmarshal([]byte(`{"nodes": [{"attributes": {"attr1": ""}}]}`), &decoded)
+					Expect(err).To(MatchError("failed to decode database: node 0 has no name"))
+				})
+			})
+
+			context("when a node appears twice", func() {
+				it("returns an error", func() {
+					var decoded libgenders.Database
+					err := json.Unmarshal([]byte(`{"nodes": [{"name": "node1"}, {"name": "node1"}]}`), &decoded)
+					Expect(err).To(MatchError(`failed to decode database: duplicate node "node1"`))
+				})
+			})
+
+			context("when a node name contains a delimiter", func() {
+				it("returns an error", func() {
+					for _, name := range []string{"bad name", "n,1", "n#1", "n[1]"} {
+						data, err := json.Marshal(map[string]any{"nodes": []map[string]string{{"name": name}}})
+						Expect(err).NotTo(HaveOccurred())
+
+						var decoded libgenders.Database
+						err = json.Unmarshal(data, &decoded)
+						Expect(err).To(MatchError(fmt.Sprintf("failed to decode database: invalid node name %q", name)))
+					}
+				})
+			})
+
+			context("when an attribute name contains a delimiter", func() {
+				it("returns an error", func() {
+					for _, key := range []string{"a b", "a=b", "a,b", "a#b"} {
+						data, err := json.Marshal(map[string]any{"nodes": []map[string]any{{"name": "node1", "attributes": map[string]string{key: "x"}}}})
+						Expect(err).NotTo(HaveOccurred())
+
+						var decoded libgenders.Database
+						err = yaml.Unmarshal(data, &decoded)
+						Expect(err).To(MatchError(fmt.Sprintf("failed to decode database: invalid attribute %q of node node1", key)))
+					}
+				})
+			})
+		})
+	})
+
+	context("YAML", func() {
+		it("round trips the database", func() {
+			data, err := yaml.Marshal(database)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(Equal(`nodes:
+    - name: node1
+      attributes:
+        attr1: ""
+        attr2: val2
+        attr3: node1
+    - name: node2
+      attributes:
+        attr1: ""
+        attr2: val2
+        attr3: node2
+`))
+
+			var decoded libgenders.Database
+			Expect(yaml.Unmarshal(data, &decoded)).To(Succeed())
+			Expect(decoded.GetNodes()).To(Equal(database.GetNodes()))
+		})
+	})
+}
@@ -0,0 +1,423 @@
+package libgenders
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ryanmoran/libgenders/internal"
+)
+
+const (
+	includeDirective = "#include"
+	byteOrderMark    = "\uFEFF"
+)
+
+type source struct {
+	path     string
+	checksum [sha256.Size]byte
+}
+
+type listing struct {
+	pattern string
+	dir     bool
+	matches []string
+}
+
+func (l listing) expand() ([]string, error) {
+	if l.dir {
+		return listDir(l.pattern)
+	}
+
+	return glob(l.pattern)
+}
+
+type loader struct {
+	ctx       context.Context
+	config    config
+	root      string
+	nodes     []Node
+	names     map[string]int
+	origins   []map[string]Definition
+	conflicts []Conflict
+	sources   []source
+	listings  []listing
+	stack     []string
+	parser    internal.Parser
+	lines     int
+	merges    int
+}
+
+func newLoader(ctx context.Context, config config, root string) *loader {
+	return &loader{
+		ctx:    ctx,
+		config: config,
+		root:   filepath.Clean(root),
+		nodes:  []Node{},
+		names:  make(map[string]int),
+	}
+}
+
+func (l *loader) loadFile(path string) error {
+	path = filepath.Clean(path)
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	l.sources = append(l.sources, source{path: path})
+	index := len(l.sources) - 1
+
+	l.stack = append(l.stack, path)
+	defer func() { l.stack = l.stack[:len(l.stack)-1] }()
+
+	hash := sha256.New()
+	reader := newLineReader(io.TeeReader(file, hash), l.config.maxLineLength)
+	defer func() { l.lines += reader.number }()
+
+	for {
+		if err := l.ctx.Err(); err != nil {
+			return fmt.Errorf("failed to load database file %s:%d: %w", path, reader.number+1, err)
+		}
+
+		line, number, err := reader.next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to scan database file %s:%d: %w", path, number, err)
+		}
+
+		if pattern, ok := cutInclude(line); ok {
+			if err := l.include(path, number, pattern); err != nil {
+				return err
+			}
+			continue
+		}
+
+		nodes, err := l.parser.Parse(line)
+		if err != nil {
+			return fmt.Errorf("failed to parse database file %s:%d: %w", path, number, err)
+		}
+
+		if err := l.merge(nodes, path, number); err != nil {
+			return err
+		}
+	}
+
+	copy(l.sources[index].checksum[:], hash.Sum(nil))
+
+	return nil
+}
+
+func (l *loader) loadDir(dir string) error {
+	paths, err := listDir(dir)
+	if err != nil {
+		return err
+	}
+	l.listings = append(l.listings, listing{pattern: filepath.Clean(dir), dir: true, matches: paths})
+
+	for _, path := range paths {
+		if err := l.loadFile(path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *loader) include(path string, number int, pattern string) error {
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(filepath.Dir(path), pattern)
+	}
+
+	matches, err := glob(pattern)
+	if err != nil {
+		return fmt.Errorf("failed to include %s at %s:%d: %w", pattern, path, number, err)
+	}
+
+	if len(matches) == 0 && !hasGlobMeta(pattern) {
+		return fmt.Errorf("failed to include %s at %s:%d: %w", pattern, path, number, os.ErrNotExist)
+	}
+	l.listings = append(l.listings, listing{pattern: pattern, matches: matches})
+
+	for _, match := range matches {
+		if slices.Contains(l.stack, filepath.Clean(match)) {
+			return fmt.Errorf("failed to include %s at %s:%d: include cycle", match, path, number)
+		}
+
+		if err := l.loadFile(match); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *loader) merge(nodes []internal.Node, path string, number int) error {
+	for _, node := range nodes {
+		index, ok := l.names[node.Name]
+		if ok {
+			l.merges++
+		} else {
+			l.nodes = append(l.nodes, Node{Name: node.Name})
+			l.origins = append(l.origins, make(map[string]Definition))
+			index = len(l.nodes) - 1
+			l.names[node.Name] = index
+		}
+
+		attributes := make(map[string]string, len(node.Attributes))
+		for _, key := range slices.Sorted(maps.Keys(node.Attributes)) {
+			current := Definition{Value: node.Attributes[key], Path: path, Line: number}
+
+			previous, ok := l.origins[index][key]
+			if ok && previous.Value != current.Value {
+				conflict := Conflict{
+					Node:      node.Name,
+					Attribute: key,
+					Previous:  previous,
+					Current:   current,
+				}
+				l.conflicts = append(l.conflicts, conflict)
+
+				replace, err := l.config.mergePolicy.resolve(conflict, l.config.conflictHandler)
+				if err != nil {
+					return err
+				}
+
+				if !replace {
+					continue
+				}
+			}
+
+			l.origins[index][key] = current
+			attributes[key] = current.Value
+		}
+
+		l.nodes[index].mergeAttributes(attributes)
+	}
+
+	return nil
+}
+
+func (l *loader) database() (Database, error) {
+	database := Database{
+		nodes:    l.nodes,
+		names:    l.names,
+		attrs:    make(map[string]internal.Set),
+		attrvals: make(map[string]internal.Set),
+		indices:  make(internal.Set, len(l.nodes)),
+
+		root:     l.root,
+		policy:   l.config.mergePolicy,
+		maxLine:  l.config.maxLineLength,
+		sources:  l.sources,
+		listings: l.listings,
+		checksum: checksum(l.sources),
+
+		conflicts: l.conflicts,
+
+		hostname: l.config.hostname,
+		altname:  l.config.altname,
+	}
+
+	for index, node := range database.nodes {
+		database.indices[index] = index
+		for key, value := range node.Attributes {
+			database.attrs[key] = append(database.attrs[key], index)
+
+			if value != "" {
+				keyval := fmt.Sprintf("%s=%s", key, value)
+				database.attrvals[keyval] = append(database.attrvals[keyval], index)
+			}
+		}
+	}
+
+	if err := database.indexNames(); err != nil {
+		return Database{}, err
+	}
+
+	return database, nil
+}
+
+type lineReader struct {
+	reader *bufio.Reader
+	max    int
+	number int
+}
+
+func newLineReader(r io.Reader, max int) *lineReader {
+	return &lineReader{reader: bufio.NewReader(r), max: max}
+}
+
+func (r *lineReader) next() (string, int, error) {
+	var (
+		line      []byte
+		number    = r.number + 1
+		continued bool
+	)
+
+	for {
+		physical, err := r.readLine(len(line))
+		if err == io.EOF && continued {
+			return string(line), number, nil
+		}
+
+		if err != nil {
+			return "", number, err
+		}
+		r.number++
+
+		if r.number == 1 {
+			physical = bytes.TrimPrefix(physical, []byte(byteOrderMark))
+		}
+
+		if err := validateLine(physical); err != nil {
+			return "", r.number, err
+		}
+
+		if continued {
+			physical = bytes.TrimLeftFunc(physical, unicode.IsSpace)
+		}
+
+		line = append(line, physical...)
+		if r.max > 0 && len(line) > r.max {
+			return "", number, r.errTooLong()
+		}
+
+		if !bytes.HasSuffix(line, []byte{'\\'}) || commented(line) {
+			return string(line), number, nil
+		}
+
+		line = line[:len(line)-1]
+		continued = true
+	}
+}
+
+func (r *lineReader) readLine(offset int) ([]byte, error) {
+	var line []byte
+	for {
+		chunk, err := r.reader.ReadSlice('\n')
+		line = append(line, chunk...)
+
+		if r.max > 0 && offset+len(bytes.TrimSuffix(line, []byte{'\n'})) > r.max {
+			return nil, r.errTooLong()
+		}
+
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+
+		if err != nil && (err != io.EOF || len(line) == 0) {
+			return nil, err
+		}
+
+		line = bytes.TrimSuffix(line, []byte{'\n'})
+		return bytes.TrimSuffix(line, []byte{'\r'}), nil
+	}
+}
+
+func (r *lineReader) errTooLong() error {
+	return fmt.Errorf("line exceeds maximum length of %d bytes", r.max)
+}
+
+func commented(line []byte) bool {
+	var quoted bool
+	for i := 0; i < len(line); i++ {
+		switch {
+		case quoted && line[i] == '\\':
+			i++
+
+		case quoted && line[i] == '"':
+			quoted = false
+
+		case line[i] == '"' && i > 0 && line[i-1] == '=':
+			quoted = true
+
+		case !quoted && line[i] == '#':
+			return true
+		}
+	}
+
+	return false
+}
+
+func validateLine(line []byte) error {
+	if !utf8.Valid(line) {
+		return errors.New("invalid UTF-8")
+	}
+
+	if i := bytes.IndexFunc(line, func(r rune) bool { return unicode.IsControl(r) && r != '\t' }); i >= 0 {
+		r, _ := utf8.DecodeRune(line[i:])
+		return fmt.Errorf("invalid control character %U", r)
+	}
+
+	return nil
+}
+
+func checksum(sources []source) [sha256.Size]byte {
+	hash := sha256.New()
+	for _, source := range sources {
+		hash.Write(source.checksum[:])
+	}
+
+	var sum [sha256.Size]byte
+	copy(sum[:], hash.Sum(nil))
+
+	return sum
+}
+
+func listDir(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			paths = append(paths, filepath.Join(dir, entry.Name()))
+		}
+	}
+
+	return paths, nil
+}
+
+func cutInclude(line string) (string, bool) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(line), includeDirective)
+	if !ok || len(rest) == 0 || (rest[0] != ' ' && rest[0] != '\t') {
+		return "", false
+	}
+
+	pattern := strings.TrimSpace(rest)
+	return pattern, len(pattern) > 0
+}
+
+func glob(pattern string) ([]string, error) {
+	matches, err := filepath.Glob(pattern)
+	if err != nil || !hasGlobMeta(pattern) {
+		return matches, err
+	}
+
+	return slices.DeleteFunc(matches, func(match string) bool {
+		info, err := os.Stat(match)
+		return err == nil && !info.Mode().IsRegular()
+	}), nil
+}
+
+func hasGlobMeta(pattern string) bool {
+	return strings.ContainsAny(pattern, `*?[\`)
+}
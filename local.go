@@ -0,0 +1,34 @@
+package libgenders
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+const DefaultAltnameAttribute = "altname"
+
+func (d Database) LocalNode() (Node, error) {
+	hostname := d.hostname
+	if hostname == nil {
+		hostname = os.Hostname
+	}
+
+	name, err := hostname()
+	if err != nil {
+		return Node{}, fmt.Errorf("failed to resolve local node: %w", err)
+	}
+
+	short, _, _ := strings.Cut(name, ".")
+	for _, candidate := range []string{name, short} {
+		if node, ok := d.GetNode(candidate); ok {
+			return node, nil
+		}
+	}
+
+	return Node{}, fmt.Errorf("failed to resolve local node %s: %w", name, ErrNodeNotFound)
+}
+
+func (d Database) Self() (Node, error) {
+	return d.LocalNode()
+}
@@ -0,0 +1,101 @@
+package libgenders_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ryanmoran/libgenders"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testLocal(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		path string
+	)
+
+	it.Before(func() {
+		path = filepath.Join(t.TempDir(), "genders")
+		Expect(os.WriteFile(path, []byte("node1 compute,altname=n1-eth0\nnode2.example.com compute\nlogin1 login,alias=portal\n"), 0600)).To(Succeed())
+	})
+
+	hostname := func(name string) libgenders.Option {
+		return libgenders.WithHostname(func() (string, error) { return name, nil })
+	}
+
+	context("LocalNode", func() {
+		it("resolves the short hostname", func() {
+			database, err := libgenders.NewDatabase(path, hostname("node1.cluster.example.com"))
+			Expect(err).NotTo(HaveOccurred())
+
+			node, err := database.LocalNode()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(node.Name).To(Equal("node1"))
+		})
+
+		it("resolves the fully qualified hostname", func() {
+			database, err := libgenders.NewDatabase(path, hostname("node2.example.com"))
+			Expect(err).NotTo(HaveOccurred())
+
+			node, err := database.Self()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(node.Name).To(Equal("node2.example.com"))
+		})
+
+		it("resolves the altname attribute", func() {
+			database, err := libgenders.NewDatabase(path, hostname("n1-eth0.cluster.example.com"))
+			Expect(err).NotTo(HaveOccurred())
+
+			node, err := database.LocalNode()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(node.Name).To(Equal("node1"))
+		})
+
+		context("when the altname attribute is configured", func() {
+			it("resolves that attribute instead", func() {
+				database, err := libgenders.NewDatabase(path, hostname("portal"), libgenders.WithAltnameAttribute("alias"))
+				Expect(err).NotTo(HaveOccurred())
+
+				node, err := database.LocalNode()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(node.Name).To(Equal("login1"))
+
+				database, err = libgenders.NewDatabase(path, hostname("n1-eth0"), libgenders.WithAltnameAttribute("alias"))
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = database.LocalNode()
+				Expect(err).To(MatchError(libgenders.ErrNodeNotFound))
+			})
+		})
+
+		context("failure cases", func() {
+			context("when the host is not in the database", func() {
+				it("returns an error", func() {
+					database, err := libgenders.NewDatabase(path, hostname("node9.example.com"))
+					Expect(err).NotTo(HaveOccurred())
+
+					_, err = database.LocalNode()
+					Expect(err).To(MatchError(libgenders.ErrNodeNotFound))
+					Expect(err).To(MatchError("failed to resolve local node node9.example.com: node not found"))
+				})
+			})
+
+			context("when the hostname cannot be determined", func() {
+				it("returns an error", func() {
+					database, err := libgenders.NewDatabase(path, libgenders.WithHostname(func() (string, error) {
+						return "", errors.New("no hostname")
+					}))
+					Expect(err).NotTo(HaveOccurred())
+
+					_, err = database.LocalNode()
+					Expect(err).To(MatchError("failed to resolve local node: no hostname"))
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,62 @@
+package libgenders
+
+import (
+	"fmt"
+	"log"
+)
+
+type MergePolicy uint8
+
+const (
+	MergeLastWins MergePolicy = iota
+	MergeFirstWins
+	MergeErrorOnConflict
+	MergeWarnOnConflict
+)
+
+type Definition struct {
+	Value string
+	Path  string
+	Line  int
+}
+
+func (d Definition) String() string {
+	return fmt.Sprintf("%q at %s:%d", d.Value, d.Path, d.Line)
+}
+
+type Conflict struct {
+	Node      string
+	Attribute string
+	Previous  Definition
+	Current   Definition
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("node %s attribute %s: %s conflicts with %s", c.Node, c.Attribute, c.Current, c.Previous)
+}
+
+type ConflictError struct {
+	Conflict Conflict
+}
+
+func (e ConflictError) Error() string {
+	return fmt.Sprintf("failed to merge %s", e.Conflict)
+}
+
+func (p MergePolicy) resolve(conflict Conflict, handler func(Conflict)) (bool, error) {
+	switch p {
+	case MergeFirstWins:
+		return false, nil
+
+	case MergeErrorOnConflict:
+		return false, ConflictError{Conflict: conflict}
+
+	case MergeWarnOnConflict:
+		if handler == nil {
+			handler = func(conflict Conflict) { log.Printf("genders: %s", conflict) }
+		}
+		handler(conflict)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,127 @@
+package libgenders_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ryanmoran/libgenders"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testMerge(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		path string
+	)
+
+	it.Before(func() {
+		path = filepath.Join(t.TempDir(), "genders")
+		Expect(os.WriteFile(path, []byte("node[1-2] attr1,attr2=valA\nnode1 attr2=valA\nnode2 attr2=valB,attr3\n"), 0600)).To(Succeed())
+	})
+
+	context("when no merge policy is given", func() {
+		it("keeps the last value and reports the conflict", func() {
+			database, err := libgenders.NewDatabase(path)
+			Expect(err).NotTo(HaveOccurred())
+
+			value, _ := database.GetNodeAttr("node2", "attr2")
+			Expect(value).To(Equal("valB"))
+
+			Expect(database.Conflicts()).To(Equal([]libgenders.Conflict{
+				{
+					Node:      "node2",
+					Attribute: "attr2",
+					Previous:  libgenders.Definition{Value: "valA", Path: path, Line: 1},
+					Current:   libgenders.Definition{Value: "valB", Path: path, Line: 3},
+				},
+			}))
+		})
+	})
+
+	context("MergeFirstWins", func() {
+		it("keeps the first value and reports the conflict", func() {
+			database, err := libgenders.NewDatabase(path, libgenders.WithMergePolicy(libgenders.MergeFirstWins))
+			Expect(err).NotTo(HaveOccurred())
+
+			value, _ := database.GetNodeAttr("node2", "attr2")
+			Expect(value).To(Equal("valA"))
+
+			_, ok := database.GetNodeAttr("node2", "attr3")
+			Expect(ok).To(BeTrue())
+
+			nodes, err := database.Query("attr2=valB")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nodes).To(BeEmpty())
+
+			Expect(database.Conflicts()).To(HaveLen(1))
+		})
+	})
+
+	context("MergeErrorOnConflict", func() {
+		it("returns an error naming both lines", func() {
+			_, err := libgenders.NewDatabase(path, libgenders.WithMergePolicy(libgenders.MergeErrorOnConflict))
+			Expect(err).To(MatchError(ContainSubstring(`failed to merge node node2 attribute attr2: "valB" at ` + path + `:3 conflicts with "valA" at ` + path + `:1`)))
+
+			var conflictErr libgenders.ConflictError
+			Expect(errors.As(err, &conflictErr)).To(BeTrue())
+			Expect(conflictErr.Conflict.Previous.Line).To(Equal(1))
+			Expect(conflictErr.Conflict.Current.Line).To(Equal(3))
+		})
+
+		context("when repeated values agree", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(path, []byte("node1 attr1=valA\nnode1 attr1=valA,attr2\n"), 0600)).To(Succeed())
+			})
+
+			it("loads the database", func() {
+				database, err := libgenders.NewDatabase(path, libgenders.WithMergePolicy(libgenders.MergeErrorOnConflict))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(database.Conflicts()).To(BeEmpty())
+			})
+		})
+	})
+
+	context("MergeWarnOnConflict", func() {
+		it("keeps the last value and calls the conflict handler", func() {
+			var conflicts []libgenders.Conflict
+			database, err := libgenders.NewDatabase(path,
+				libgenders.WithMergePolicy(libgenders.MergeWarnOnConflict),
+				libgenders.WithConflictHandler(func(conflict libgenders.Conflict) {
+					conflicts = append(conflicts, conflict)
+				}),
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			value, _ := database.GetNodeAttr("node2", "attr2")
+			Expect(value).To(Equal("valB"))
+
+			Expect(conflicts).To(Equal(database.Conflicts()))
+			Expect(conflicts).To(HaveLen(1))
+		})
+	})
+
+	context("when the conflicting entries are in different files", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(filepath.Join(filepath.Dir(path), "included"), []byte("\nnode1 attr2=valC\n"), 0600)).To(Succeed())
+			Expect(os.WriteFile(path, []byte("node1 attr2=valA\n#include included\n"), 0600)).To(Succeed())
+		})
+
+		it("reports the file of each entry", func() {
+			database, err := libgenders.NewDatabase(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(database.Conflicts()).To(Equal([]libgenders.Conflict{
+				{
+					Node:      "node1",
+					Attribute: "attr2",
+					Previous:  libgenders.Definition{Value: "valA", Path: path, Line: 1},
+					Current:   libgenders.Definition{Value: "valC", Path: filepath.Join(filepath.Dir(path), "included"), Line: 2},
+				},
+			}))
+		})
+	})
+}
@@ -1,12 +1,32 @@
 package libgenders
 
+import (
+	"iter"
+	"maps"
+	"slices"
+)
+
 type Node struct {
 	Name       string
 	Attributes map[string]string
 }
 
-func (n Node) mergeAttributes(attributes map[string]string) {
+func (n *Node) mergeAttributes(attributes map[string]string) {
+	if n.Attributes == nil && len(attributes) > 0 {
+		n.Attributes = make(map[string]string, len(attributes))
+	}
+
 	for key, value := range attributes {
 		n.Attributes[key] = value
 	}
 }
+
+func (n Node) Attrs() iter.Seq2[string, string] {
+	return func(yield func(string, string) bool) {
+		for _, key := range slices.Sorted(maps.Keys(n.Attributes)) {
+			if !yield(key, n.Attributes[key]) {
+				return
+			}
+		}
+	}
+}
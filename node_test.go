@@ -0,0 +1,48 @@
+package libgenders_test
+
+import (
+	"testing"
+
+	"github.com/ryanmoran/libgenders"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testNode(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	context("Attrs", func() {
+		it("yields the attributes in sorted order", func() {
+			node := libgenders.Node{
+				Name:       "node1",
+				Attributes: map[string]string{"os": "rhel9", "compute": "", "rack": "r4"},
+			}
+
+			var pairs [][2]string
+			for key, value := range node.Attrs() {
+				pairs = append(pairs, [2]string{key, value})
+			}
+			Expect(pairs).To(Equal([][2]string{{"compute", ""}, {"os", "rhel9"}, {"rack", "r4"}}))
+		})
+
+		it("stops when the consumer stops", func() {
+			node := libgenders.Node{Name: "node1", Attributes: map[string]string{"a": "", "b": ""}}
+
+			var keys []string
+			for key := range node.Attrs() {
+				keys = append(keys, key)
+				break
+			}
+			Expect(keys).To(Equal([]string{"a"}))
+		})
+
+		context("when the node has no attributes", func() {
+			it("yields nothing", func() {
+				for range (libgenders.Node{Name: "node1"}).Attrs() {
+					t.Fatal("unexpected attribute")
+				}
+			})
+		})
+	})
+}
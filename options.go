@@ -0,0 +1,62 @@
+package libgenders
+
+import "os"
+
+type Option func(*config)
+
+type config struct {
+	mergePolicy     MergePolicy
+	conflictHandler func(Conflict)
+	cachePath       string
+	maxLineLength   int
+	hostname        func() (string, error)
+	altname         string
+}
+
+func newConfig(options []Option) config {
+	c := config{
+		hostname: os.Hostname,
+		altname:  DefaultAltnameAttribute,
+	}
+	for _, option := range options {
+		option(&c)
+	}
+
+	return c
+}
+
+func WithMergePolicy(policy MergePolicy) Option {
+	return func(c *config) {
+		c.mergePolicy = policy
+	}
+}
+
+func WithConflictHandler(handler func(Conflict)) Option {
+	return func(c *config) {
+		c.conflictHandler = handler
+	}
+}
+
+func WithCache(path string) Option {
+	return func(c *config) {
+		c.cachePath = path
+	}
+}
+
+func WithMaxLineLength(length int) Option {
+	return func(c *config) {
+		c.maxLineLength = length
+	}
+}
+
+func WithHostname(hostname func() (string, error)) Option {
+	return func(c *config) {
+		c.hostname = hostname
+	}
+}
+
+func WithAltnameAttribute(attr string) Option {
+	return func(c *config) {
+		c.altname = attr
+	}
+}
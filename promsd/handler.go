@@ -0,0 +1,98 @@
+package promsd
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ryanmoran/libgenders"
+)
+
+const labelPrefix = "__meta_genders_"
+
+type TargetGroup struct {
+	Targets []string          `json:"targets"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+type Handler struct {
+	handle *libgenders.Handle
+	query  string
+	port   int
+}
+
+func NewHandler(handle *libgenders.Handle, query string, port int) (Handler, error) {
+	if _, err := handle.Load().Query(query); err != nil {
+		return Handler{}, err
+	}
+
+	return Handler{
+		handle: handle,
+		query:  query,
+		port:   port,
+	}, nil
+}
+
+func (h Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	groups, err := h.TargetGroups()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(groups)
+}
+
+func (h Handler) TargetGroups() ([]TargetGroup, error) {
+	nodes, err := h.handle.Load().Query(h.query)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]TargetGroup, 0, len(nodes))
+	for _, node := range nodes {
+		target := node.Name
+		if h.port != 0 {
+			target = net.JoinHostPort(node.Name, strconv.Itoa(h.port))
+		}
+
+		labels := map[string]string{labelPrefix + "node": node.Name}
+		for key, value := range node.Attrs() {
+			label := labelPrefix + labelName(key)
+			if _, ok := labels[label]; ok || key == "" {
+				continue
+			}
+
+			if value == "" {
+				value = "true"
+			}
+			labels[label] = value
+		}
+
+		groups = append(groups, TargetGroup{
+			Targets: []string{target},
+			Labels:  labels,
+		})
+	}
+
+	return groups, nil
+}
+
+func labelName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+
+		return '_'
+	}, name)
+}
@@ -0,0 +1,155 @@
+package promsd_test
+
+import (
+	gocontext "context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ryanmoran/libgenders"
+	"github.com/ryanmoran/libgenders/promsd"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testHandler(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		path   string
+		handle *libgenders.Handle
+		server *httptest.Server
+	)
+
+	it.Before(func() {
+		path = filepath.Join(t.TempDir(), "genders")
+		Expect(os.WriteFile(path, []byte("node[1-3] compute,rack=r1\nnode2 drain\nnode3 gpu-type=a100\n"), 0600)).To(Succeed())
+
+		var err error
+		handle, err = libgenders.NewHandle(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler, err := promsd.NewHandler(handle, "compute && ~drain", 9100)
+		Expect(err).NotTo(HaveOccurred())
+
+		server = httptest.NewServer(handler)
+	})
+
+	it.After(func() {
+		server.Close()
+	})
+
+	get := func() (int, string) {
+		response, err := http.Get(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+		defer response.Body.Close()
+
+		body, err := io.ReadAll(response.Body)
+		Expect(err).NotTo(HaveOccurred())
+
+		return response.StatusCode, string(body)
+	}
+
+	it("serves the nodes matching the query as target groups", func() {
+		status, body := get()
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON(`[
+			{
+				"targets": ["node1:9100"],
+				"labels": {
+					"__meta_genders_node": "node1",
+					"__meta_genders_compute": "true",
+					"__meta_genders_rack": "r1"
+				}
+			},
+			{
+				"targets": ["node3:9100"],
+				"labels": {
+					"__meta_genders_node": "node3",
+					"__meta_genders_compute": "true",
+					"__meta_genders_rack": "r1",
+					"__meta_genders_gpu_type": "a100"
+				}
+			}
+		]`))
+	})
+
+	it("serves the latest database from the handle", func() {
+		Expect(os.WriteFile(path, []byte("node1 compute\n"), 0600)).To(Succeed())
+		Expect(handle.Reload(gocontext.Background())).To(Succeed())
+
+		_, body := get()
+		Expect(body).To(MatchJSON(`[{"targets": ["node1:9100"], "labels": {"__meta_genders_node": "node1", "__meta_genders_compute": "true"}}]`))
+	})
+
+	context("when no port is given", func() {
+		it("serves bare node names as targets", func() {
+			handler, err := promsd.NewHandler(handle, "gpu-type=a100", 0)
+			Expect(err).NotTo(HaveOccurred())
+
+			groups, err := handler.TargetGroups()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(groups).To(HaveLen(1))
+			Expect(groups[0].Targets).To(Equal([]string{"node3"}))
+		})
+	})
+
+	context("when attribute labels collide", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(path, []byte("node1 node=other,a_b=2,a-b=1,a.b=3\n"), 0600)).To(Succeed())
+			Expect(handle.Reload(gocontext.Background())).To(Succeed())
+		})
+
+		it("keeps the node label and the first attribute in sorted order", func() {
+			handler, err := promsd.NewHandler(handle, "node", 0)
+			Expect(err).NotTo(HaveOccurred())
+
+			for range 10 {
+				groups, err := handler.TargetGroups()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(groups).To(Equal([]promsd.TargetGroup{
+					{
+						Targets: []string{"node1"},
+						Labels: map[string]string{
+							"__meta_genders_node": "node1",
+							"__meta_genders_a_b":  "1",
+						},
+					},
+				}))
+			}
+		})
+	})
+
+	context("when no nodes match", func() {
+		it("serves an empty list", func() {
+			handler, err := promsd.NewHandler(handle, "no-such-attr", 9100)
+			Expect(err).NotTo(HaveOccurred())
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+			Expect(recorder.Body.String()).To(MatchJSON(`[]`))
+		})
+	})
+
+	context("failure cases", func() {
+		context("when the request method is not GET", func() {
+			it("returns method not allowed", func() {
+				response, err := http.Post(server.URL, "application/json", nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response.Body.Close()).To(Succeed())
+				Expect(response.StatusCode).To(Equal(http.StatusMethodNotAllowed))
+			})
+		})
+
+		context("when the query is invalid", func() {
+			it("returns an error", func() {
+				_, err := promsd.NewHandler(handle, "(compute", 9100)
+				Expect(err).To(MatchError(ContainSubstring("failed to tokenize query")))
+			})
+		})
+	})
+}
@@ -0,0 +1,14 @@
+package promsd_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestPromsd(t *testing.T) {
+	suite := spec.New(" libgenders/promsd", spec.Report(report.Terminal{}))
+	suite("Handler", testHandler)
+	suite.Run(t)
+}
@@ -0,0 +1,49 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"text/template"
+
+	"github.com/ryanmoran/libgenders"
+)
+
+func Funcs(database libgenders.Database) template.FuncMap {
+	return template.FuncMap{
+		"query": database.Query,
+		"nodes": database.GetNodes,
+		"attr": func(name, attr string) string {
+			value, _ := database.GetNodeAttr(name, attr)
+			return value
+		},
+		"hostlist": func(value any) (string, error) {
+			switch value := value.(type) {
+			case []libgenders.Node:
+				names := make([]string, 0, len(value))
+				for _, node := range value {
+					names = append(names, node.Name)
+				}
+				return libgenders.CompressHostlist(names), nil
+
+			case []string:
+				return libgenders.CompressHostlist(value), nil
+
+			default:
+				return "", fmt.Errorf("hostlist: expected nodes or names, got %T", value)
+			}
+		},
+	}
+}
+
+func Parse(name, text string, database libgenders.Database) (*template.Template, error) {
+	return template.New(name).Funcs(Funcs(database)).Parse(text)
+}
+
+func Execute(w io.Writer, name, text string, database libgenders.Database) error {
+	tmpl, err := Parse(name, text, database)
+	if err != nil {
+		return err
+	}
+
+	return tmpl.Execute(w, database)
+}
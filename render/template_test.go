@@ -0,0 +1,78 @@
+package render_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ryanmoran/libgenders"
+	"github.com/ryanmoran/libgenders/render"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testTemplate(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		database libgenders.Database
+	)
+
+	it.Before(func() {
+		path := filepath.Join(t.TempDir(), "genders")
+		Expect(os.WriteFile(path, []byte(`node[1-4] compute
+node[3-4] gpu
+node3 ip=10.0.0.3
+node4 ip=10.0.0.4
+login1 login
+`), 0600)).To(Succeed())
+
+		var err error
+		database, err = libgenders.NewDatabase(path)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it("renders query results with node attributes", func() {
+		var buffer bytes.Buffer
+		Expect(render.Execute(&buffer, "gpu", `{{range query "gpu"}}{{.Name}} {{attr .Name "ip"}}
+{{end}}`, database)).To(Succeed())
+		Expect(buffer.String()).To(Equal("node3 10.0.0.3\nnode4 10.0.0.4\n"))
+	})
+
+	it("compresses nodes and names into hostlists", func() {
+		var buffer bytes.Buffer
+		Expect(render.Execute(&buffer, "hostlist", `{{hostlist nodes}} {{with query "compute"}}{{hostlist .}}{{end}}`, database)).To(Succeed())
+		Expect(buffer.String()).To(Equal("node[1-4],login1 node[1-4]"))
+	})
+
+	it("executes the template with the database as data", func() {
+		var buffer bytes.Buffer
+		Expect(render.Execute(&buffer, "attrs", `{{range .GetAttrs}}{{.}} {{end}}`, database)).To(Succeed())
+		Expect(buffer.String()).To(Equal("compute gpu ip login "))
+	})
+
+	context("failure cases", func() {
+		context("when the template cannot be parsed", func() {
+			it("returns an error", func() {
+				err := render.Execute(&bytes.Buffer{}, "broken", `{{range}}`, database)
+				Expect(err).To(MatchError(ContainSubstring("missing value for range")))
+			})
+		})
+
+		context("when a query is invalid", func() {
+			it("returns an error", func() {
+				err := render.Execute(&bytes.Buffer{}, "query", `{{query "a &&"}}`, database)
+				Expect(err).To(MatchError(ContainSubstring(`error calling query`)))
+			})
+		})
+
+		context("when hostlist is given something other than nodes", func() {
+			it("returns an error", func() {
+				err := render.Execute(&bytes.Buffer{}, "hostlist", `{{hostlist 1}}`, database)
+				Expect(err).To(MatchError(ContainSubstring("hostlist: expected nodes or names, got int")))
+			})
+		})
+	})
+}
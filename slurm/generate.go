@@ -0,0 +1,85 @@
+package slurm
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/ryanmoran/libgenders"
+)
+
+type Config struct {
+	Query     string
+	Fields    map[string]string
+	Partition string
+}
+
+func Generate(w io.Writer, database libgenders.Database, config Config) error {
+	attrs := slices.Sorted(maps.Keys(config.Fields))
+	fields := make(map[string]string, len(attrs))
+	for _, attr := range attrs {
+		field := config.Fields[attr]
+		if other, ok := fields[field]; ok {
+			return fmt.Errorf("failed to generate slurm config: attributes %s and %s both map to field %s", other, attr, field)
+		}
+		fields[field] = attr
+	}
+
+	nodes := database.GetNodes()
+	if config.Query != "" {
+		var err error
+		nodes, err = database.Query(config.Query)
+		if err != nil {
+			return err
+		}
+	}
+
+	slices.SortFunc(attrs, func(a, b string) int {
+		return strings.Compare(config.Fields[a], config.Fields[b])
+	})
+
+	var (
+		lines      []string
+		names      = make(map[string][]string)
+		partitions = make(map[string][]string)
+	)
+
+	for _, node := range nodes {
+		var fields []string
+		for _, attr := range attrs {
+			if value, ok := node.Attributes[attr]; ok && value != "" {
+				fields = append(fields, fmt.Sprintf("%s=%s", config.Fields[attr], value))
+			}
+		}
+
+		line := strings.Join(fields, " ")
+		if _, ok := names[line]; !ok {
+			lines = append(lines, line)
+		}
+		names[line] = append(names[line], node.Name)
+
+		if config.Partition != "" {
+			if partition := node.Attributes[config.Partition]; partition != "" {
+				partitions[partition] = append(partitions[partition], node.Name)
+			}
+		}
+	}
+
+	var output strings.Builder
+	for _, line := range lines {
+		fmt.Fprintf(&output, "NodeName=%s", libgenders.CompressHostlist(names[line]))
+		if line != "" {
+			fmt.Fprintf(&output, " %s", line)
+		}
+		output.WriteString("\n")
+	}
+
+	for _, partition := range slices.Sorted(maps.Keys(partitions)) {
+		fmt.Fprintf(&output, "PartitionName=%s Nodes=%s\n", partition, libgenders.CompressHostlist(partitions[partition]))
+	}
+
+	_, err := io.WriteString(w, output.String())
+	return err
+}
@@ -0,0 +1,103 @@
+package slurm_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ryanmoran/libgenders"
+	"github.com/ryanmoran/libgenders/slurm"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testGenerate(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		database libgenders.Database
+		config   slurm.Config
+	)
+
+	it.Before(func() {
+		path := filepath.Join(t.TempDir(), "genders")
+		Expect(os.WriteFile(path, []byte(`node[1-8] compute,cpus=64,mem=256000,partition=batch
+node[7-8] gres=gpu:4,partition=gpu
+node8 drain
+login1 login,cpus=16
+`), 0600)).To(Succeed())
+
+		var err error
+		database, err = libgenders.NewDatabase(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		config = slurm.Config{
+			Query: "compute",
+			Fields: map[string]string{
+				"cpus": "CPUs",
+				"mem":  "RealMemory",
+				"gres": "Gres",
+			},
+			Partition: "partition",
+		}
+	})
+
+	it("writes NodeName and PartitionName lines with compressed hostlists", func() {
+		var buffer bytes.Buffer
+		Expect(slurm.Generate(&buffer, database, config)).To(Succeed())
+		Expect(buffer.String()).To(Equal(`NodeName=node[1-6] CPUs=64 RealMemory=256000
+NodeName=node[7-8] CPUs=64 Gres=gpu:4 RealMemory=256000
+PartitionName=batch Nodes=node[1-6]
+PartitionName=gpu Nodes=node[7-8]
+`))
+	})
+
+	context("when the query excludes nodes", func() {
+		it.Before(func() {
+			config.Query = "compute && ~drain"
+		})
+
+		it("only writes the matching nodes", func() {
+			var buffer bytes.Buffer
+			Expect(slurm.Generate(&buffer, database, config)).To(Succeed())
+			Expect(buffer.String()).To(ContainSubstring("NodeName=node7 CPUs=64 Gres=gpu:4 RealMemory=256000\n"))
+			Expect(buffer.String()).To(ContainSubstring("PartitionName=gpu Nodes=node7\n"))
+		})
+	})
+
+	context("when no query or partition is given", func() {
+		it.Before(func() {
+			config.Query = ""
+			config.Partition = ""
+		})
+
+		it("writes every node without partitions", func() {
+			var buffer bytes.Buffer
+			Expect(slurm.Generate(&buffer, database, config)).To(Succeed())
+			Expect(buffer.String()).To(Equal(`NodeName=node[1-6] CPUs=64 RealMemory=256000
+NodeName=node[7-8] CPUs=64 Gres=gpu:4 RealMemory=256000
+NodeName=login1 CPUs=16
+`))
+		})
+	})
+
+	context("failure cases", func() {
+		context("when the query is invalid", func() {
+			it("returns an error", func() {
+				config.Query = "(compute"
+				err := slurm.Generate(&bytes.Buffer{}, database, config)
+				Expect(err).To(MatchError(ContainSubstring("failed to tokenize query")))
+			})
+		})
+
+		context("when two attributes map to the same field", func() {
+			it("returns an error", func() {
+				config.Fields["cores"] = "CPUs"
+				err := slurm.Generate(&bytes.Buffer{}, database, config)
+				Expect(err).To(MatchError("failed to generate slurm config: attributes cores and cpus both map to field CPUs"))
+			})
+		})
+	})
+}
@@ -0,0 +1,14 @@
+package slurm_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestSlurm(t *testing.T) {
+	suite := spec.New(" libgenders/slurm", spec.Report(report.Terminal{}))
+	suite("Generate", testGenerate)
+	suite.Run(t)
+}
@@ -0,0 +1,163 @@
+package libgenders
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+type WatchEvent struct {
+	Database Database
+	Changes  Changes
+	Err      error
+}
+
+type Watcher struct {
+	path     string
+	interval time.Duration
+	handle   *Handle
+
+	mutex       sync.Mutex
+	files       map[string]fileState
+	subscribers []func(WatchEvent)
+}
+
+func NewWatcher(path string, interval time.Duration, options ...Option) (*Watcher, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("failed to create watcher: interval must be positive, got %s", interval)
+	}
+
+	watcher := &Watcher{
+		path:     path,
+		interval: interval,
+		handle:   &Handle{path: path, options: options},
+	}
+
+	if _, err := watcher.load(); err != nil {
+		return nil, err
+	}
+
+	return watcher, nil
+}
+
+func (w *Watcher) Database() Database {
+	return *w.handle.Load()
+}
+
+func (w *Watcher) Handle() *Handle {
+	return w.handle
+}
+
+func (w *Watcher) Subscribe(subscriber func(WatchEvent)) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.subscribers = append(w.subscribers, subscriber)
+}
+
+func (w *Watcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case <-ticker.C:
+			_, _ = w.Check()
+		}
+	}
+}
+
+func (w *Watcher) Check() (bool, error) {
+	w.mutex.Lock()
+	previous := w.handle.Load()
+	changed, err := w.load()
+	current := w.handle.Load()
+	subscribers := w.subscribers
+	w.mutex.Unlock()
+
+	var event WatchEvent
+	switch {
+	case err != nil:
+		event = WatchEvent{Database: *previous, Err: err}
+
+	case changed:
+		event = WatchEvent{Database: *current, Changes: Diff(*previous, *current)}
+
+	default:
+		return false, nil
+	}
+
+	for _, subscriber := range subscribers {
+		subscriber(event)
+	}
+
+	return changed, err
+}
+
+func (w *Watcher) load() (bool, error) {
+	previous := w.handle.Load()
+
+	files := map[string]fileState{filepath.Clean(w.path): statFile(w.path)}
+	for path := range w.files {
+		files[path] = statFile(path)
+	}
+
+	if previous != nil && maps.Equal(files, w.files) && listed(previous.listings) {
+		return false, nil
+	}
+	w.files = files
+
+	database, err := NewDatabase(w.path, w.handle.options...)
+	if err != nil {
+		return false, err
+	}
+
+	w.files = make(map[string]fileState, len(database.sources))
+	for _, source := range database.sources {
+		state, ok := files[source.path]
+		if !ok {
+			state = statFile(source.path)
+		}
+		w.files[source.path] = state
+	}
+
+	if previous != nil && database.checksum == previous.checksum {
+		return false, nil
+	}
+
+	w.handle.Replace(database)
+	return true, nil
+}
+
+func listed(listings []listing) bool {
+	for _, listing := range listings {
+		matches, err := listing.expand()
+		if err != nil || !slices.Equal(matches, listing.matches) {
+			return false
+		}
+	}
+
+	return true
+}
+
+type fileState struct {
+	modTime int64
+	size    int64
+}
+
+func statFile(path string) fileState {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+
+	return fileState{modTime: info.ModTime().UnixNano(), size: info.Size()}
+}
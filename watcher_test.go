@@ -0,0 +1,189 @@
+package libgenders_test
+
+import (
+	gocontext "context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ryanmoran/libgenders"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testWatcher(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		path    string
+		watcher *libgenders.Watcher
+		events  []libgenders.WatchEvent
+	)
+
+	write := func(content string, modTime time.Time) {
+		Expect(os.WriteFile(path, []byte(content), 0600)).To(Succeed())
+		Expect(os.Chtimes(path, modTime, modTime)).To(Succeed())
+	}
+
+	it.Before(func() {
+		path = filepath.Join(t.TempDir(), "genders")
+		write("node1 attr1\n", time.Now().Add(-time.Hour))
+
+		var err error
+		watcher, err = libgenders.NewWatcher(path, 10*time.Millisecond)
+		Expect(err).NotTo(HaveOccurred())
+
+		events = nil
+		watcher.Subscribe(func(event libgenders.WatchEvent) {
+			events = append(events, event)
+		})
+	})
+
+	it("loads the database", func() {
+		value, ok := watcher.Database().GetNodeAttr("node1", "attr1")
+		Expect(ok).To(BeTrue())
+		Expect(value).To(BeEmpty())
+	})
+
+	context("Check", func() {
+		it("swaps in the changed database and notifies subscribers", func() {
+			write("node1 attr1=val1\nnode2 attr1\n", time.Now())
+
+			changed, err := watcher.Check()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changed).To(BeTrue())
+
+			value, ok := watcher.Database().GetNodeAttr("node1", "attr1")
+			Expect(ok).To(BeTrue())
+			Expect(value).To(Equal("val1"))
+			Expect(watcher.Handle().Load().GetNodes()).To(HaveLen(2))
+
+			Expect(events).To(HaveLen(1))
+			Expect(events[0].Err).NotTo(HaveOccurred())
+			Expect(events[0].Changes.Added).To(Equal([]string{"node2"}))
+			Expect(events[0].Changes.Nodes).To(Equal([]libgenders.NodeChange{
+				{
+					Name:    "node1",
+					Changed: map[string]libgenders.ValueChange{"attr1": {Old: "", New: "val1"}},
+				},
+			}))
+		})
+
+		context("when the file has not changed", func() {
+			it("does not notify subscribers", func() {
+				changed, err := watcher.Check()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(changed).To(BeFalse())
+				Expect(events).To(BeEmpty())
+			})
+		})
+
+		context("when only the modification time has changed", func() {
+			it("does not notify subscribers", func() {
+				write("node1 attr1\n", time.Now())
+
+				changed, err := watcher.Check()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(changed).To(BeFalse())
+				Expect(events).To(BeEmpty())
+			})
+		})
+
+		context("when an included file changes", func() {
+			it("swaps in the changed database", func() {
+				included := filepath.Join(filepath.Dir(path), "included")
+				Expect(os.WriteFile(included, []byte("node2 attr2\n"), 0600)).To(Succeed())
+				write("node1 attr1\n#include included\n", time.Now())
+
+				changed, err := watcher.Check()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(changed).To(BeTrue())
+				Expect(watcher.Database().GetNodes()).To(HaveLen(2))
+
+				Expect(os.WriteFile(included, []byte("node2 attr2\nnode3 attr3\n"), 0600)).To(Succeed())
+
+				changed, err = watcher.Check()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(changed).To(BeTrue())
+				Expect(watcher.Database().GetNodes()).To(HaveLen(3))
+				Expect(events).To(HaveLen(2))
+				Expect(events[1].Changes.Added).To(Equal([]string{"node3"}))
+			})
+		})
+
+		context("when a new file matches an include pattern", func() {
+			it("swaps in the changed database", func() {
+				dir := filepath.Join(filepath.Dir(path), "genders.d")
+				Expect(os.Mkdir(dir, 0700)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(dir, "a.conf"), []byte("node2 attr2\n"), 0600)).To(Succeed())
+				write("node1 attr1\n#include genders.d/*.conf\n", time.Now())
+
+				changed, err := watcher.Check()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(changed).To(BeTrue())
+				Expect(watcher.Database().GetNodes()).To(HaveLen(2))
+
+				Expect(os.WriteFile(filepath.Join(dir, "new.conf"), []byte("node3 attr3\n"), 0600)).To(Succeed())
+
+				changed, err = watcher.Check()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(changed).To(BeTrue())
+				Expect(watcher.Database().GetNodes()).To(HaveLen(3))
+				Expect(events[1].Changes.Added).To(Equal([]string{"node3"}))
+			})
+		})
+
+		context("when the file cannot be parsed", func() {
+			it("keeps the previous database and notifies subscribers of the error", func() {
+				write("node[%%-%%] attr1\n", time.Now())
+
+				changed, err := watcher.Check()
+				Expect(err).To(MatchError(ContainSubstring("failed to parse database file")))
+				Expect(changed).To(BeFalse())
+
+				_, ok := watcher.Database().GetNodeAttr("node1", "attr1")
+				Expect(ok).To(BeTrue())
+
+				Expect(events).To(HaveLen(1))
+				Expect(events[0].Err).To(MatchError(ContainSubstring("failed to parse database file")))
+				Expect(events[0].Database.GetNodes()).To(HaveLen(1))
+			})
+		})
+	})
+
+	context("Run", func() {
+		it("polls the file until the context is done", func() {
+			ctx, cancel := gocontext.WithCancel(gocontext.Background())
+			done := make(chan error)
+			go func() { done <- watcher.Run(ctx) }()
+
+			write("node1 attr1\nnode2 attr1\n", time.Now())
+			Eventually(func() int { return len(watcher.Database().GetNodes()) }).Should(Equal(2))
+
+			cancel()
+			Expect(<-done).To(MatchError(gocontext.Canceled))
+		})
+	})
+
+	context("failure cases", func() {
+		context("when the file does not exist", func() {
+			it("returns an error", func() {
+				_, err := libgenders.NewWatcher("no-such-file", time.Second)
+				Expect(err).To(MatchError(ContainSubstring("no-such-file: no such file or directory")))
+			})
+		})
+
+		context("when the interval is not positive", func() {
+			it("returns an error", func() {
+				_, err := libgenders.NewWatcher(path, 0)
+				Expect(err).To(MatchError("failed to create watcher: interval must be positive, got 0s"))
+
+				_, err = libgenders.NewWatcher(path, -time.Second)
+				Expect(err).To(MatchError("failed to create watcher: interval must be positive, got -1s"))
+			})
+		})
+	})
+}
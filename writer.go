@@ -0,0 +1,65 @@
+package libgenders
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+func (d Database) WriteTo(w io.Writer) (int64, error) {
+	var (
+		lines []string
+		names = make(map[string][]string)
+	)
+
+	for _, node := range d.nodes {
+		line := formatAttributes(node.Attributes)
+		if _, ok := names[line]; !ok {
+			lines = append(lines, line)
+		}
+		names[line] = append(names[line], node.Name)
+	}
+
+	var output strings.Builder
+	for _, line := range lines {
+		output.WriteString(CompressHostlist(names[line]))
+		if len(line) > 0 {
+			output.WriteString(" ")
+			output.WriteString(line)
+		}
+		output.WriteString("\n")
+	}
+
+	n, err := io.WriteString(w, output.String())
+	return int64(n), err
+}
+
+func formatAttributes(attributes map[string]string) string {
+	var attrs []string
+	for _, key := range slices.Sorted(maps.Keys(attributes)) {
+		value := attributes[key]
+		if value == "" {
+			attrs = append(attrs, key)
+			continue
+		}
+
+		attrs = append(attrs, fmt.Sprintf("%s=%s", key, formatValue(value)))
+	}
+
+	return strings.Join(attrs, ",")
+}
+
+func formatValue(value string) string {
+	value = strings.ReplaceAll(value, "%", "%%")
+	if strings.HasPrefix(value, `"`) || strings.HasSuffix(value, `\`) || strings.ContainsFunc(value, func(r rune) bool {
+		return r == ',' || r == '#' || unicode.IsSpace(r) || !unicode.IsPrint(r)
+	}) {
+		return strconv.Quote(value)
+	}
+
+	return value
+}
@@ -0,0 +1,84 @@
+package libgenders_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ryanmoran/libgenders"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testWriter(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	context("WriteTo", func() {
+		it("writes nodes with the same attributes as a hostrange", func() {
+			database, err := libgenders.NewDatabase("./testdata/genders.query_1")
+			Expect(err).NotTo(HaveOccurred())
+
+			var buffer bytes.Buffer
+			_, err = database.WriteTo(&buffer)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(buffer.String()).To(Equal(`node[1,3] attr1,attr2=val2,attr3,attr4=val4,attr7,attr8=val8
+node[2,4] attr1,attr10=val10,attr2=val2,attr3,attr4=val4,attr9
+node[5,7] attr1,attr2=val2,attr5,attr6=val6,attr7,attr8=val8
+node[6,8] attr1,attr10=val10,attr2=val2,attr5,attr6=val6,attr9
+`))
+		})
+
+		it("quotes values containing delimiters", func() {
+			database, err := libgenders.NewDatabase("./testdata/genders.quoted_values")
+			Expect(err).NotTo(HaveOccurred())
+
+			var buffer bytes.Buffer
+			_, err = database.WriteTo(&buffer)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(buffer.String()).To(Equal(`node1 desc="GPU node, rack 4",opts=a=b=c,path=C:\tmp,say="\"hi\"",url="http://x/#frag"
+node2 desc="node2 in rack 4",plain=x"y
+`))
+		})
+
+		it("quotes values ending with a backslash", func() {
+			path := filepath.Join(t.TempDir(), "genders")
+			Expect(os.WriteFile(path, []byte(`node1 path="C:\\",say="# C:\\" # C:\`+"\nnode2 dir=a\\b\n"), 0600)).To(Succeed())
+
+			database, err := libgenders.NewDatabase(path)
+			Expect(err).NotTo(HaveOccurred())
+
+			var buffer bytes.Buffer
+			_, err = database.WriteTo(&buffer)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(buffer.String()).To(Equal(`node1 path="C:\\",say="# C:\\"
+node2 dir=a\b
+`))
+
+			Expect(os.WriteFile(path, buffer.Bytes(), 0600)).To(Succeed())
+
+			written, err := libgenders.NewDatabase(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(written.GetNodes()).To(Equal(database.GetNodes()))
+		})
+
+		it("round trips the database", func() {
+			for _, filename := range []string{"genders.query_2_hostrange", "genders.subst_escape_char", "genders.nodes_only_many", "genders.equal_sign_in_value", "genders.quoted_values"} {
+				database, err := libgenders.NewDatabase(filepath.Join("./testdata", filename))
+				Expect(err).NotTo(HaveOccurred())
+
+				var buffer bytes.Buffer
+				_, err = database.WriteTo(&buffer)
+				Expect(err).NotTo(HaveOccurred())
+
+				path := filepath.Join(t.TempDir(), filename)
+				Expect(os.WriteFile(path, buffer.Bytes(), 0600)).To(Succeed())
+
+				written, err := libgenders.NewDatabase(path)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(written.GetNodes()).To(ConsistOf(database.GetNodes()), filename)
+			}
+		})
+	})
+}